@@ -0,0 +1,127 @@
+// Disassembles a ROM into RGBDS assembly.
+//
+//	gbdis -file dmg_boot.bin [-symbols boot.sym] [-o boot.asm]
+//
+// Code is found from the entry point and the RST and interrupt vectors.
+// Functions only reached through pointers can be added with -entry, and jump
+// tables with -table, as a bank:address pair in hex like in .sym files, or an
+// address in bank 0 or 1:
+//
+//	gbdis -file game.gb -entry 0x1234 -entry 05:4000 -table 0x0456,8
+package main
+
+import (
+	"flag"
+	"fmt"
+	"gopherboy/pkg/disassembler"
+	"gopherboy/pkg/symbols"
+	"os"
+	"strconv"
+	"strings"
+)
+
+var file string
+var outFile string
+var symbolFile string
+var origin string
+var linear bool
+var entries []disassembler.Location
+var tables []disassembler.JumpTable
+
+func main() {
+	flag.StringVar(&file, "file", "", "The ROM to disassemble.")
+	flag.StringVar(&outFile, "o", "", "Write the assembly to this file instead of stdout.")
+	flag.StringVar(&symbolFile, "symbols", "", "RGBDS .sym or .map file whose labels are used in the output.")
+	flag.StringVar(&origin, "origin", "0", "Address the first byte of the file is mapped at.")
+	flag.BoolVar(&linear, "linear", false, "Decode the whole file as code instead of following the control flow.")
+	flag.Func("entry", "Additional code address, may be repeated.", func(s string) error {
+		loc, err := parseLocation(s)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, loc)
+		return nil
+	})
+	flag.Func("table", "Jump table as address,entries, may be repeated.", func(s string) error {
+		addr, n, ok := strings.Cut(s, ",")
+		if !ok {
+			return fmt.Errorf("invalid jump table %q", s)
+		}
+		loc, err := parseLocation(addr)
+		if err != nil {
+			return err
+		}
+		count, err := strconv.ParseUint(n, 0, 16)
+		if err != nil {
+			return fmt.Errorf("invalid jump table %q", s)
+		}
+		tables = append(tables, disassembler.JumpTable{Location: loc, Len: int(count)})
+		return nil
+	})
+	flag.Parse()
+
+	if file == "" {
+		fmt.Fprintln(os.Stderr, "usage: gbdis -file rom.gb [-symbols rom.sym] [-o rom.asm]")
+		os.Exit(2)
+	}
+	if err := run(); err != nil {
+		fmt.Fprintf(os.Stderr, "gbdis: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// Parses bank:addr, or an address which is in bank 1 from 0x4000.
+func parseLocation(s string) (disassembler.Location, error) {
+	if bank, addr, ok := strings.Cut(s, ":"); ok {
+		b, err := strconv.ParseUint(bank, 16, 16)
+		if err != nil {
+			return disassembler.Location{}, fmt.Errorf("invalid bank %q", bank)
+		}
+		a, err := strconv.ParseUint(addr, 16, 16)
+		if err != nil {
+			return disassembler.Location{}, fmt.Errorf("invalid address %q", addr)
+		}
+		return disassembler.Location{Bank: uint16(b), Addr: uint16(a)}, nil
+	}
+	a, err := strconv.ParseUint(s, 0, 16)
+	if err != nil {
+		return disassembler.Location{}, fmt.Errorf("invalid address %q", s)
+	}
+	loc := disassembler.Location{Addr: uint16(a)}
+	if a >= 0x4000 {
+		loc.Bank = 1
+	}
+	return loc, nil
+}
+
+func run() error {
+	rom, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	org, err := strconv.ParseUint(origin, 0, 16)
+	if err != nil {
+		return fmt.Errorf("invalid origin %q", origin)
+	}
+	opts := disassembler.Options{
+		Origin:     uint16(org),
+		Linear:     linear,
+		Entries:    entries,
+		JumpTables: tables,
+	}
+	if symbolFile != "" {
+		if opts.Symbols, err = symbols.Load(symbolFile); err != nil {
+			return err
+		}
+	}
+	listing := disassembler.Disassemble(rom, opts)
+	out := os.Stdout
+	if outFile != "" {
+		if out, err = os.Create(outFile); err != nil {
+			return err
+		}
+		defer out.Close()
+	}
+	_, err = listing.WriteTo(out)
+	return err
+}
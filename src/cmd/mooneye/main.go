@@ -0,0 +1,261 @@
+// Runs the mooneye-test-suite ROMs and tabulates the results by category.
+//
+//	mooneye -dir mts/ [-model dmg] [-v]
+//
+// dir is a build of the suite, with the acceptance/ and emulator-only/
+// directories. A test ends at the LD B,B software breakpoint and passes when
+// it leaves the Fibonacci numbers 3, 5, 8, 13, 21, 34 in B, C, D, E, H and L.
+// Tests meant for other models, according to the suffix of their name, are
+// skipped. Output is sorted so results can be diffed across commits.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"gopherboy/pkg/common"
+	"gopherboy/pkg/gameboy"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+)
+
+// LD B,B, used by the suite as a software breakpoint.
+const breakpointOpcode = 0x40
+
+var fibonacci = [6]byte{3, 5, 8, 13, 21, 34}
+
+// Display names of the test directories, others are shown as they are.
+var categoryNames = map[string]string{
+	"oam_dma": "OAM DMA",
+	"ppu":     "PPU",
+	"mbc1":    "MBC",
+	"mbc2":    "MBC",
+	"mbc5":    "MBC",
+}
+
+// Parts of the name suffixes of tests which run on each model: -GS, -dmgABC,
+// -cgb...
+var modelTags = map[gameboy.Model][]string{
+	gameboy.ModelDMG0: {"G", "dmg0"},
+	gameboy.ModelDMG:  {"G", "dmgABC"},
+	gameboy.ModelMGB:  {"G", "mgb"},
+	gameboy.ModelSGB:  {"S", "sgb"},
+	gameboy.ModelCGB:  {"C", "cgb"},
+}
+
+type status string
+
+const (
+	statusPass    status = "pass"
+	statusFail    status = "fail"
+	statusError   status = "error"
+	statusTimeout status = "timeout"
+	statusSkip    status = "skip"
+)
+
+var statuses = []status{statusPass, statusFail, statusError, statusTimeout, statusSkip}
+
+type result struct {
+	path     string
+	category string
+	status   status
+	detail   string
+}
+
+var dir string
+var model string
+var timeout int
+var verbose bool
+
+func main() {
+	flag.StringVar(&dir, "dir", "", "Directory of the built mooneye-test-suite ROMs.")
+	flag.StringVar(&model, "model", "dmg", "Hardware model to test: dmg0, dmg, mgb, sgb or cgb.")
+	flag.IntVar(&timeout, "timeout", 10, "Emulated seconds before a test is stopped.")
+	flag.BoolVar(&verbose, "v", false, "List the result of every test.")
+	flag.Parse()
+
+	m, err := gameboy.ParseModel(model)
+	if err != nil || dir == "" {
+		fmt.Fprintln(os.Stderr, "usage: mooneye -dir mts/ [-model dmg] [-timeout secs] [-v]")
+		os.Exit(2)
+	}
+	paths, err := findTests(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "mooneye: %v\n", err)
+		os.Exit(2)
+	}
+	results := runAll(paths, m)
+	if verbose {
+		printResults(results)
+	}
+	printSummary(results)
+	for _, r := range results {
+		if r.status != statusPass && r.status != statusSkip {
+			os.Exit(1)
+		}
+	}
+}
+
+func findTests(root string) ([]string, error) {
+	var paths []string
+	for _, sub := range []string{"acceptance", "emulator-only"} {
+		err := filepath.WalkDir(filepath.Join(root, sub), func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && strings.EqualFold(filepath.Ext(path), ".gb") {
+				paths = append(paths, path)
+			}
+			return nil
+		})
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no test ROMs found under %s", root)
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// Runs the tests on all CPUs, results are in the order of paths.
+func runAll(paths []string, m gameboy.Model) []result {
+	results := make([]result, len(paths))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < runtime.NumCPU(); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = runTest(paths[i], m)
+			}
+		}()
+	}
+	for i := range paths {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
+
+func runTest(path string, m gameboy.Model) result {
+	rel, _ := filepath.Rel(dir, path)
+	r := result{path: filepath.ToSlash(rel), category: category(rel)}
+	if !runsOn(filepath.Base(path), m) {
+		r.status = statusSkip
+		return r
+	}
+	gb := gameboy.NewGB("", path, false)
+	// Fail fast instead of timing out on a locked up CPU.
+	gb.CPU.IllegalOpcodePolicy = gameboy.IllegalOpError
+	gb.SkipBoot(m)
+	if err := gb.Init(); err != nil {
+		r.status, r.detail = statusError, err.Error()
+		return r
+	}
+	limit := uint64(timeout) * common.ClkFrequency
+	for cycles := uint64(0); cycles < limit; {
+		if gb.MMU.Peek(gb.CPU.PC) == breakpointOpcode {
+			cpu := gb.CPU
+			regs := [6]byte{cpu.BC.Hi(), cpu.BC.Lo(), cpu.DE.Hi(), cpu.DE.Lo(), cpu.HL.Hi(), cpu.HL.Lo()}
+			if regs == fibonacci {
+				r.status = statusPass
+			} else {
+				r.status = statusFail
+				r.detail = fmt.Sprintf("B=%02x C=%02x D=%02x E=%02x H=%02x L=%02x", regs[0], regs[1], regs[2], regs[3], regs[4], regs[5])
+			}
+			return r
+		}
+		n, err := gb.Step()
+		if err != nil {
+			r.status, r.detail = statusError, err.Error()
+			return r
+		}
+		cycles += uint64(n)
+	}
+	r.status = statusTimeout
+	return r
+}
+
+// Category of a test from its directory, e.g. acceptance/timer/div_write.gb
+// is in "timer".
+func category(rel string) string {
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	if len(parts) < 3 {
+		return "misc"
+	}
+	name := parts[len(parts) - 2]
+	if display, ok := categoryNames[name]; ok {
+		return display
+	}
+	return name
+}
+
+// Whether a test runs on model m, judging from the suffix of its name.
+// Tests without a suffix run everywhere.
+func runsOn(name string, m gameboy.Model) bool {
+	name = strings.TrimSuffix(name, filepath.Ext(name))
+	i := strings.LastIndex(name, "-")
+	if i < 0 {
+		return true
+	}
+	tag := name[i+1:]
+	// Upper case model letters (GS, C, ...) or lower case model names
+	// (dmgABC, cgb, ...).
+	if strings.ToUpper(tag) == tag {
+		return strings.Contains(tag, modelTags[m][0])
+	}
+	return strings.Contains(tag, modelTags[m][1])
+}
+
+func printResults(results []result) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	for _, r := range results {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", r.path, r.status, r.detail)
+	}
+	w.Flush()
+	fmt.Println()
+}
+
+func printSummary(results []result) {
+	counts := map[string]map[status]int{}
+	var categories []string
+	total := map[status]int{}
+	for _, r := range results {
+		if counts[r.category] == nil {
+			counts[r.category] = map[status]int{}
+			categories = append(categories, r.category)
+		}
+		counts[r.category][r.status]++
+		total[r.status]++
+	}
+	sort.Strings(categories)
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprint(w, "category\t")
+	for _, s := range statuses {
+		fmt.Fprintf(w, "%s\t", s)
+	}
+	fmt.Fprintln(w, "total\t")
+	row := func(name string, c map[status]int) {
+		fmt.Fprintf(w, "%s\t", name)
+		n := 0
+		for _, s := range statuses {
+			fmt.Fprintf(w, "%d\t", c[s])
+			n += c[s]
+		}
+		fmt.Fprintf(w, "%d\t\n", n)
+	}
+	for _, c := range categories {
+		row(c, counts[c])
+	}
+	row("all", total)
+	w.Flush()
+}
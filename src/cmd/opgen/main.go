@@ -0,0 +1,211 @@
+// Generates the instruction tables in pkg/common from Opcodes.json, the
+// opcode description maintained at https://gbdev.io/gb-opcodes/Opcodes.json.
+//
+//	opgen -in Opcodes.json -out opcodes_gen.go
+//
+// Run through go generate in pkg/common, which reads the copy in util/.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"strconv"
+	"strings"
+)
+
+var in string
+var out string
+
+type jsonOperand struct {
+	Name      string `json:"name"`
+	Bytes     int    `json:"bytes"`
+	Immediate bool   `json:"immediate"`
+	Increment bool   `json:"increment"`
+	Decrement bool   `json:"decrement"`
+}
+
+type jsonOpcode struct {
+	Mnemonic string            `json:"mnemonic"`
+	Bytes    int               `json:"bytes"`
+	// Clocks, followed by the clocks when the condition fails for
+	// conditional instructions.
+	Cycles   []int             `json:"cycles"`
+	Operands []jsonOperand     `json:"operands"`
+	Flags    map[string]string `json:"flags"`
+}
+
+type jsonOpcodes struct {
+	Unprefixed map[string]jsonOpcode `json:"unprefixed"`
+	CBPrefixed map[string]jsonOpcode `json:"cbprefixed"`
+}
+
+func main() {
+	flag.StringVar(&in, "in", "Opcodes.json", "Path of Opcodes.json.")
+	flag.StringVar(&out, "out", "opcodes_gen.go", "Go file to write.")
+	flag.Parse()
+
+	if err := generate(); err != nil {
+		fmt.Fprintf(os.Stderr, "opgen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func generate() error {
+	data, err := os.ReadFile(in)
+	if err != nil {
+		return err
+	}
+	var ops jsonOpcodes
+	if err := json.Unmarshal(data, &ops); err != nil {
+		return fmt.Errorf("%s: %w", in, err)
+	}
+	unprefixed, err := table(ops.Unprefixed)
+	if err != nil {
+		return fmt.Errorf("unprefixed: %w", err)
+	}
+	prefixed, err := table(ops.CBPrefixed)
+	if err != nil {
+		return fmt.Errorf("cbprefixed: %w", err)
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by opgen from Opcodes.json. DO NOT EDIT.\n\npackage common\n\n")
+	writeOpcodes(&buf, "Opcodes", "Opcodes describes each unprefixed opcode.", unprefixed)
+	writeOpcodes(&buf, "CBOpcodes", "CBOpcodes describes each opcode following the 0xCB prefix.", prefixed)
+	writeLookup(&buf, "InstrDebugLookup", unprefixed)
+	writeLookup(&buf, "PrefixInstrDebugLookup", prefixed)
+	writeInts(&buf, "OpcodeCycles", "OpcodeCycles is the number of M-cycles each unprefixed opcode takes, when\n// the condition fails for conditional instructions.", "[]int", unprefixed, func(op *jsonOpcode) int {
+		return notTaken(op)
+	})
+	writeInts(&buf, "CBOpcodeCycles", "CBOpcodeCycles is the number of M-cycles each prefixed opcode takes,\n// including the prefix.", "[]int", prefixed, func(op *jsonOpcode) int {
+		return notTaken(op)
+	})
+	writeInts(&buf, "InstrLen", "InstrLen is the length in bytes of each unprefixed instruction, operands\n// included.", "[0x100]int", unprefixed, func(op *jsonOpcode) int {
+		return op.Bytes
+	})
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("formatting output: %w", err)
+	}
+	return os.WriteFile(out, src, 0644)
+}
+
+// Orders the opcodes of a JSON table, checking that none is missing.
+func table(entries map[string]jsonOpcode) (*[0x100]jsonOpcode, error) {
+	var ops [0x100]jsonOpcode
+	seen := 0
+	for key, op := range entries {
+		n, err := strconv.ParseUint(key, 0, 8)
+		if err != nil {
+			return nil, fmt.Errorf("invalid opcode %q", key)
+		}
+		if len(op.Cycles) == 0 || op.Cycles[0] % 4 != 0 {
+			return nil, fmt.Errorf("opcode %s: invalid cycles %v", key, op.Cycles)
+		}
+		ops[n] = op
+		seen++
+	}
+	if seen != len(ops) {
+		return nil, fmt.Errorf("%d opcodes, want %d", seen, len(ops))
+	}
+	return &ops, nil
+}
+
+func taken(op *jsonOpcode) int {
+	return op.Cycles[0] / 4
+}
+
+func notTaken(op *jsonOpcode) int {
+	return op.Cycles[len(op.Cycles) - 1] / 4
+}
+
+// Flag effects in Z, N, H, C order.
+func flags(op *jsonOpcode) string {
+	var s strings.Builder
+	for _, f := range []string{"Z", "N", "H", "C"} {
+		effect := op.Flags[f]
+		if effect == "" {
+			effect = "-"
+		}
+		s.WriteString(effect[:1])
+	}
+	return s.String()
+}
+
+// The instruction as shown in debug output, e.g. "LDI [HL],A". The format is
+// the one of the tables which used to be maintained by hand.
+func debugText(op *jsonOpcode) string {
+	mnemonic := op.Mnemonic
+	var operands []string
+	for _, o := range op.Operands {
+		name := o.Name
+		if !o.Immediate {
+			name = "[" + name + "]"
+		}
+		if o.Decrement {
+			mnemonic += "D"
+		} else if o.Increment {
+			mnemonic += "I"
+		}
+		operands = append(operands, name)
+	}
+	return mnemonic + " " + strings.Join(operands, ",")
+}
+
+func writeOpcodes(buf *bytes.Buffer, name, doc string, ops *[0x100]jsonOpcode) {
+	fmt.Fprintf(buf, "// %s\nvar %s = [0x100]Opcode{\n", doc, name)
+	for i := range ops {
+		op := &ops[i]
+		fmt.Fprintf(buf, "\t0x%02X: {Mnemonic: %q, Bytes: %d, Cycles: %d, CyclesNotTaken: %d, Flags: %q", i, op.Mnemonic, op.Bytes, taken(op), notTaken(op), flags(op))
+		if len(op.Operands) > 0 {
+			buf.WriteString(", Operands: []Operand{")
+			for j, o := range op.Operands {
+				if j > 0 {
+					buf.WriteString(", ")
+				}
+				fmt.Fprintf(buf, "{Name: %q", o.Name)
+				if o.Bytes > 0 {
+					fmt.Fprintf(buf, ", Bytes: %d", o.Bytes)
+				}
+				if o.Immediate {
+					buf.WriteString(", Immediate: true")
+				}
+				if o.Increment {
+					buf.WriteString(", Increment: true")
+				}
+				if o.Decrement {
+					buf.WriteString(", Decrement: true")
+				}
+				buf.WriteString("}")
+			}
+			buf.WriteString("}")
+		}
+		buf.WriteString("},\n")
+	}
+	buf.WriteString("}\n\n")
+}
+
+func writeLookup(buf *bytes.Buffer, name string, ops *[0x100]jsonOpcode) {
+	fmt.Fprintf(buf, "/* [DEBUG] Opcode to instruction map */\nvar %s = [0x100]string{\n", name)
+	for i := range ops {
+		fmt.Fprintf(buf, "\t0x%02X: %q,\n", i, debugText(&ops[i]))
+	}
+	buf.WriteString("}\n\n")
+}
+
+// Writes a table of numbers, 16 to a row like the opcode tables in the docs.
+func writeInts(buf *bytes.Buffer, name, doc, typ string, ops *[0x100]jsonOpcode, value func(*jsonOpcode) int) {
+	fmt.Fprintf(buf, "// %s\nvar %s = %s{\n", doc, name, typ)
+	for row := 0; row < 0x10; row++ {
+		buf.WriteString("\t")
+		for col := 0; col < 0x10; col++ {
+			fmt.Fprintf(buf, "%d, ", value(&ops[row * 0x10 + col]))
+		}
+		fmt.Fprintf(buf, "// %x\n", row)
+	}
+	buf.WriteString("}\n\n")
+}
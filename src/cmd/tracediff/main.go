@@ -0,0 +1,190 @@
+// Runs a ROM in lockstep with a reference execution trace and reports the
+// first instruction where GopherBoy diverges from it.
+//
+//	tracediff -cartridge cpu_instrs.gb -ref reference.log
+//
+// Reference traces are Gameboy Doctor logs or binary traces, see
+// gameboy.TraceEntry. They must start at the cartridge entry point with the
+// post-boot state of -model.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"gopherboy/pkg/gameboy"
+	"io"
+	"os"
+	"strings"
+)
+
+var cartridge string
+var refPath string
+var refFormat string
+var model string
+var symbolFile string
+var contextLines int
+
+// An instruction which executed successfully.
+type step struct {
+	n     int
+	state gameboy.TraceEntry
+	instr string
+}
+
+func main() {
+	flag.StringVar(&cartridge, "cartridge", "", "The ROM to run.")
+	flag.StringVar(&refPath, "ref", "", "The reference trace.")
+	flag.StringVar(&refFormat, "format", "auto", "Format of the reference trace: auto, doctor or binary.")
+	flag.StringVar(&model, "model", "dmg", "Hardware model whose post-boot state the trace starts from.")
+	flag.StringVar(&symbolFile, "symbols", "", "RGBDS .sym or .map file used to label addresses.")
+	flag.IntVar(&contextLines, "context", 10, "Number of instructions shown before the mismatch.")
+	flag.Parse()
+
+	if cartridge == "" || refPath == "" {
+		fmt.Fprintln(os.Stderr, "usage: tracediff -cartridge rom.gb -ref trace.log")
+		os.Exit(2)
+	}
+	diverged, err := run()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "tracediff: %v\n", err)
+		os.Exit(2)
+	}
+	if diverged {
+		os.Exit(1)
+	}
+}
+
+// Reports whether the emulator diverged from the reference.
+func run() (bool, error) {
+	f, err := os.Open(refPath)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+	br := bufio.NewReader(f)
+	format := gameboy.DetectTraceFormat(br)
+	if refFormat != "auto" {
+		if format, err = gameboy.ParseTraceFormat(refFormat); err != nil {
+			return false, err
+		}
+	}
+	ref := gameboy.NewTraceReader(br, format)
+
+	gb, err := newGB()
+	if err != nil {
+		return false, err
+	}
+	// Step emulation in lockstep, keeping the last instructions around for
+	// context.
+	history := make([]step, 0, contextLines)
+	for n := 1; ; n++ {
+		want, err := ref.Next()
+		if err == io.EOF {
+			fmt.Printf("No divergence in %d instructions\n", n - 1)
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		got := gb.CPU.TraceEntry()
+		if got != want {
+			reportMismatch(gb, n, history, want, got)
+			return true, nil
+		}
+		ii, _ := gb.CPU.DecodeAt(got.PC)
+		cur := step{n: n, state: got, instr: ii.Text(gb.CPU)}
+		if err := stepInstruction(gb); err != nil {
+			printHistory(gb, append(history, cur))
+			fmt.Printf("\nEmulation stopped at instruction %d: %v\n%s", n, err, gb.FormatBacktrace())
+			return true, nil
+		}
+		if contextLines > 0 {
+			if len(history) == contextLines {
+				history = history[1:]
+			}
+			history = append(history, cur)
+		}
+	}
+}
+
+// Steps past the next instruction. Traces have no entries while the CPU is
+// halted, so those steps are run along with it.
+func stepInstruction(gb *gameboy.GB) error {
+	if _, err := gb.Step(); err != nil {
+		return err
+	}
+	for gb.CPU.Halted() {
+		if _, err := gb.Step(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newGB() (*gameboy.GB, error) {
+	m, err := gameboy.ParseModel(model)
+	if err != nil {
+		return nil, err
+	}
+	gb := gameboy.NewGB("", cartridge, false)
+	gb.SkipBoot(m)
+	if symbolFile != "" {
+		if err := gb.LoadSymbols(symbolFile); err != nil {
+			return nil, err
+		}
+	}
+	if err := gb.Init(); err != nil {
+		return nil, err
+	}
+	// Reference traces are taken with LY stuck at 0x90.
+	gb.PPU.StubLY = true
+	return gb, nil
+}
+
+func printHistory(gb *gameboy.GB, history []step) {
+	if len(history) == 0 {
+		return
+	}
+	fmt.Println("Previous instructions:")
+	for _, s := range history {
+		fmt.Printf("%10d  %s  %-24s %s\n", s.n, s.state, s.instr, label(gb, s.state.PC))
+	}
+}
+
+func reportMismatch(gb *gameboy.GB, n int, history []step, want, got gameboy.TraceEntry) {
+	fmt.Printf("Mismatch at instruction %d\n\n", n)
+	printHistory(gb, history)
+	fmt.Printf("\nExpected: %s\nActual:   %s\n", want, got)
+	fmt.Printf("          %s\n", diffMarkers(want.String(), got.String()))
+	if len(history) > 0 {
+		// The state is logged before each instruction, so the previous one
+		// produced the difference.
+		last := history[len(history) - 1]
+		fmt.Printf("\nLast instruction: %#04x  %s  %s\n", last.state.PC, last.instr, label(gb, last.state.PC))
+	}
+}
+
+// Underlines the fields which differ between two trace lines.
+func diffMarkers(want, got string) string {
+	wantFields, gotFields := strings.Fields(want), strings.Fields(got)
+	var out strings.Builder
+	for i := range gotFields {
+		marker := " "
+		if i < len(wantFields) && wantFields[i] != gotFields[i] {
+			marker = "^"
+		}
+		if i > 0 {
+			out.WriteByte(' ')
+		}
+		out.WriteString(strings.Repeat(marker, len(gotFields[i])))
+	}
+	return strings.TrimRight(out.String(), " ")
+}
+
+func label(gb *gameboy.GB, addr uint16) string {
+	if l, ok := gb.Label(addr); ok {
+		return "<" + l + ">"
+	}
+	return ""
+}
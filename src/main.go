@@ -3,29 +3,236 @@ package main
 import (
 	"flag"
 	"fmt"
+	"gopherboy/pkg/dap"
+	"gopherboy/pkg/debugger"
 	"gopherboy/pkg/gameboy"
+	"gopherboy/pkg/gdbstub"
 	"os"
+	"os/signal"
+	"time"
 )
 
 var bootRom string
 var cartridge string
 var debug bool
+var illegalOp string
+var skipBoot bool
+var model string
+var noAccessBlocking bool
+var recordMovie string
+var replayMovie string
+var runDebugger bool
+var gdbAddr string
+var dapAddr string
+var symbolFile string
+var traceFile string
+var traceFormat string
 
 func main() {
 	flag.StringVar(&bootRom, "boot_rom", "../roms/dmg_boot.bin", "The path for the boot rom binary.")
 	flag.StringVar(&cartridge, "cartridge", "../roms/dmg_boot.bin", "The path for dmg game cartridge.")
 	flag.BoolVar(&debug, "debug", false, "Whether to print debug logs or not.")
+	flag.BoolVar(&skipBoot, "skip_boot", false, "Start at the cartridge entry point without running a boot rom.")
+	flag.StringVar(&model, "model", "dmg", "Hardware model whose post-boot state is used with -skip_boot: dmg0, dmg, mgb, sgb or cgb.")
+	flag.BoolVar(&noAccessBlocking, "no_access_blocking", false, "Let the CPU access VRAM and OAM in every PPU mode (debugging only).")
+	flag.StringVar(&recordMovie, "record", "", "Record a movie of the session to this file.")
+	flag.StringVar(&replayMovie, "replay", "", "Replay a movie file and verify it against its checkpoints.")
+	flag.BoolVar(&runDebugger, "debugger", false, "Start an interactive debugger instead of running freely.")
+	flag.StringVar(&gdbAddr, "gdb", "", "Serve the GDB remote protocol on this address (e.g. localhost:2345) instead of running freely.")
+	flag.StringVar(&dapAddr, "dap", "", "Serve the Debug Adapter Protocol on this address (e.g. localhost:4711) for editor debugging.")
+	flag.StringVar(&symbolFile, "symbols", "", "RGBDS .sym or .map file used to label addresses in debug output.")
+	flag.StringVar(&traceFile, "trace", "", "Write a Gameboy Doctor trace of every instruction to this file (use with -skip_boot).")
+	flag.StringVar(&traceFormat, "trace_format", "doctor", "Format of the -trace file: doctor or binary.")
+	flag.StringVar(&illegalOp, "illegal_op", "lock", "What to do on an illegal opcode: lock, error or break.")
 	
 	flag.Parse()
 	
 	fmt.Printf("Debug: %v\n", debug)
-	gb := gameboy.NewGB(bootRom, cartridge, debug)
-	if err := gb.Init(); err != nil {
+	if dapAddr != "" {
+		if err := serveDAP(); err != nil {
+			fmt.Printf("%v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	gb, err := newGB(cartridge)
+	if err != nil {
 		fmt.Printf("%v\n", err)
 		os.Exit(1)
 	}
-	if err := gb.Emulate(); err != nil {
-		fmt.Printf("Stopped emulation: %v\n", err)
+	if traceFile != "" {
+		format, err := gameboy.ParseTraceFormat(traceFormat)
+		if err != nil {
+			fmt.Printf("%v\n", err)
+			os.Exit(1)
+		}
+		f, err := os.Create(traceFile)
+		if err != nil {
+			fmt.Printf("%v\n", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		gb.StartTrace(f, format)
+	}
+	err = run(gb)
+	if traceErr := gb.StopTrace(); traceErr != nil && err == nil {
+		err = fmt.Errorf("writing trace: %v", traceErr)
+	}
+	if err != nil {
+		fmt.Printf("%v\n", err)
 		os.Exit(1)
 	}
-}
\ No newline at end of file
+}
+
+// Runs the machine in the mode selected by the flags.
+func run(gb *gameboy.GB) error {
+	if runDebugger {
+		if err := debugger.New(gb, os.Stdin, os.Stdout).Run(); err != nil {
+			return fmt.Errorf("debugger: %v", err)
+		}
+		return nil
+	}
+	if gdbAddr != "" {
+		if err := gdbstub.New(gb).ListenAndServe(gdbAddr); err != nil {
+			return fmt.Errorf("GDB stub: %v", err)
+		}
+		return nil
+	}
+	if replayMovie != "" {
+		if err := replay(gb, replayMovie); err != nil {
+			return fmt.Errorf("replay failed: %v", err)
+		}
+		return nil
+	}
+	if recordMovie != "" {
+		if err := record(gb, recordMovie); err != nil {
+			return fmt.Errorf("stopped recording: %v", err)
+		}
+		return nil
+	}
+	if gb.Tracing() {
+		if err := runTraced(gb); err != nil {
+			return fmt.Errorf("stopped emulation: %v\n%s", err, gb.FormatBacktrace())
+		}
+		return nil
+	}
+	if err := gb.Emulate(); err != nil {
+		return fmt.Errorf("stopped emulation: %v\n%s", err, gb.FormatBacktrace())
+	}
+	return nil
+}
+
+// Runs unthrottled until interrupted, so the trace can be flushed on Ctrl-C.
+func runTraced(gb *gameboy.GB) error {
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+	for {
+		select {
+		case <-interrupt:
+			return nil
+		default:
+		}
+		if err := gb.RunFrame(); err != nil {
+			return err
+		}
+	}
+}
+
+// Serves editors. Launch requests build a machine for their program, attach
+// requests use the one built from -cartridge when it is given.
+func serveDAP() error {
+	var gb *gameboy.GB
+	if flagSet("cartridge") {
+		var err error
+		if gb, err = newGB(cartridge); err != nil {
+			return err
+		}
+	}
+	if err := dap.New(gb, newGB).ListenAndServe(dapAddr); err != nil {
+		return fmt.Errorf("DAP server: %v", err)
+	}
+	return nil
+}
+
+// Whether the named flag was given on the command line.
+func flagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// Builds a machine for the cartridge, configured from the command line flags.
+func newGB(cartridge string) (*gameboy.GB, error) {
+	policy, err := gameboy.ParseIllegalOpcodePolicy(illegalOp)
+	if err != nil {
+		return nil, err
+	}
+	gb := gameboy.NewGB(bootRom, cartridge, debug)
+	gb.CPU.IllegalOpcodePolicy = policy
+	gb.MMU.DisableAccessBlocking = noAccessBlocking
+	if skipBoot {
+		m, err := gameboy.ParseModel(model)
+		if err != nil {
+			return nil, err
+		}
+		gb.SkipBoot(m)
+	}
+	if symbolFile != "" {
+		if err := gb.LoadSymbols(symbolFile); err != nil {
+			return nil, err
+		}
+	}
+	if err := gb.Init(); err != nil {
+		return nil, err
+	}
+	return gb, nil
+}
+
+func replay(gb *gameboy.GB, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	player, err := gb.PlayMovie(f)
+	if err != nil {
+		return err
+	}
+	if err := player.Verify(); err != nil {
+		return err
+	}
+	fmt.Printf("Replayed %d frames without divergence\n", player.Frames())
+	return nil
+}
+
+// Records until interrupted. There is no input frontend yet, so every frame
+// is recorded with no buttons pressed.
+func record(gb *gameboy.GB, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	recorder, err := gb.RecordMovie(f, uint64(time.Now().Unix()))
+	if err != nil {
+		return err
+	}
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	for {
+		select {
+		case <-interrupt:
+			return recorder.Close()
+		default:
+		}
+		if err := recorder.RunFrame(0); err != nil {
+			recorder.Close()
+			return err
+		}
+	}
+}
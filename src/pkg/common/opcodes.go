@@ -0,0 +1,31 @@
+package common
+
+//go:generate go run gopherboy/cmd/opgen -in ../../../util/Opcodes.json -out opcodes_gen.go
+
+// Opcode describes an instruction, see Opcodes and CBOpcodes.
+type Opcode struct {
+	Mnemonic string
+	// Length of the instruction, operands included.
+	Bytes int
+	// M-cycles the instruction takes, and when the condition fails for
+	// conditional jumps, calls and returns. Both are equal for the others.
+	Cycles int
+	CyclesNotTaken int
+	Operands []Operand
+	// Effect on the Z, N, H and C flags: '-' unchanged, '0' reset, '1' set
+	// or the flag's letter when it depends on the result, e.g. "Z0HC".
+	Flags string
+}
+
+type Operand struct {
+	// Register, condition, RST vector or placeholder for an immediate (n8,
+	// n16, a8, a16, e8).
+	Name string
+	// Size of the immediate following the opcode, 0 for other operands.
+	Bytes int
+	// False when the operand is dereferenced, [HL].
+	Immediate bool
+	// HL is incremented or decremented after the access, LD [HL+],A.
+	Increment bool
+	Decrement bool
+}
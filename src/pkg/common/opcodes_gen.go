@@ -0,0 +1,1106 @@
+// Code generated by opgen from Opcodes.json. DO NOT EDIT.
+
+package common
+
+// Opcodes describes each unprefixed opcode.
+var Opcodes = [0x100]Opcode{
+	0x00: {Mnemonic: "NOP", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----"},
+	0x01: {Mnemonic: "LD", Bytes: 3, Cycles: 3, CyclesNotTaken: 3, Flags: "----", Operands: []Operand{{Name: "BC", Immediate: true}, {Name: "n16", Bytes: 2, Immediate: true}}},
+	0x02: {Mnemonic: "LD", Bytes: 1, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "BC"}, {Name: "A", Immediate: true}}},
+	0x03: {Mnemonic: "INC", Bytes: 1, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "BC", Immediate: true}}},
+	0x04: {Mnemonic: "INC", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z0H-", Operands: []Operand{{Name: "B", Immediate: true}}},
+	0x05: {Mnemonic: "DEC", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z1H-", Operands: []Operand{{Name: "B", Immediate: true}}},
+	0x06: {Mnemonic: "LD", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "B", Immediate: true}, {Name: "n8", Bytes: 1, Immediate: true}}},
+	0x07: {Mnemonic: "RLCA", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "000C"},
+	0x08: {Mnemonic: "LD", Bytes: 3, Cycles: 5, CyclesNotTaken: 5, Flags: "----", Operands: []Operand{{Name: "a16", Bytes: 2}, {Name: "SP", Immediate: true}}},
+	0x09: {Mnemonic: "ADD", Bytes: 1, Cycles: 2, CyclesNotTaken: 2, Flags: "-0HC", Operands: []Operand{{Name: "HL", Immediate: true}, {Name: "BC", Immediate: true}}},
+	0x0A: {Mnemonic: "LD", Bytes: 1, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "BC"}}},
+	0x0B: {Mnemonic: "DEC", Bytes: 1, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "BC", Immediate: true}}},
+	0x0C: {Mnemonic: "INC", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z0H-", Operands: []Operand{{Name: "C", Immediate: true}}},
+	0x0D: {Mnemonic: "DEC", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z1H-", Operands: []Operand{{Name: "C", Immediate: true}}},
+	0x0E: {Mnemonic: "LD", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "C", Immediate: true}, {Name: "n8", Bytes: 1, Immediate: true}}},
+	0x0F: {Mnemonic: "RRCA", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "000C"},
+	0x10: {Mnemonic: "STOP", Bytes: 2, Cycles: 1, CyclesNotTaken: 1, Flags: "----", Operands: []Operand{{Name: "n8", Bytes: 1, Immediate: true}}},
+	0x11: {Mnemonic: "LD", Bytes: 3, Cycles: 3, CyclesNotTaken: 3, Flags: "----", Operands: []Operand{{Name: "DE", Immediate: true}, {Name: "n16", Bytes: 2, Immediate: true}}},
+	0x12: {Mnemonic: "LD", Bytes: 1, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "DE"}, {Name: "A", Immediate: true}}},
+	0x13: {Mnemonic: "INC", Bytes: 1, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "DE", Immediate: true}}},
+	0x14: {Mnemonic: "INC", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z0H-", Operands: []Operand{{Name: "D", Immediate: true}}},
+	0x15: {Mnemonic: "DEC", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z1H-", Operands: []Operand{{Name: "D", Immediate: true}}},
+	0x16: {Mnemonic: "LD", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "D", Immediate: true}, {Name: "n8", Bytes: 1, Immediate: true}}},
+	0x17: {Mnemonic: "RLA", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "000C"},
+	0x18: {Mnemonic: "JR", Bytes: 2, Cycles: 3, CyclesNotTaken: 3, Flags: "----", Operands: []Operand{{Name: "e8", Bytes: 1, Immediate: true}}},
+	0x19: {Mnemonic: "ADD", Bytes: 1, Cycles: 2, CyclesNotTaken: 2, Flags: "-0HC", Operands: []Operand{{Name: "HL", Immediate: true}, {Name: "DE", Immediate: true}}},
+	0x1A: {Mnemonic: "LD", Bytes: 1, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "DE"}}},
+	0x1B: {Mnemonic: "DEC", Bytes: 1, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "DE", Immediate: true}}},
+	0x1C: {Mnemonic: "INC", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z0H-", Operands: []Operand{{Name: "E", Immediate: true}}},
+	0x1D: {Mnemonic: "DEC", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z1H-", Operands: []Operand{{Name: "E", Immediate: true}}},
+	0x1E: {Mnemonic: "LD", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "E", Immediate: true}, {Name: "n8", Bytes: 1, Immediate: true}}},
+	0x1F: {Mnemonic: "RRA", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "000C"},
+	0x20: {Mnemonic: "JR", Bytes: 2, Cycles: 3, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "NZ", Immediate: true}, {Name: "e8", Bytes: 1, Immediate: true}}},
+	0x21: {Mnemonic: "LD", Bytes: 3, Cycles: 3, CyclesNotTaken: 3, Flags: "----", Operands: []Operand{{Name: "HL", Immediate: true}, {Name: "n16", Bytes: 2, Immediate: true}}},
+	0x22: {Mnemonic: "LD", Bytes: 1, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "HL", Increment: true}, {Name: "A", Immediate: true}}},
+	0x23: {Mnemonic: "INC", Bytes: 1, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "HL", Immediate: true}}},
+	0x24: {Mnemonic: "INC", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z0H-", Operands: []Operand{{Name: "H", Immediate: true}}},
+	0x25: {Mnemonic: "DEC", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z1H-", Operands: []Operand{{Name: "H", Immediate: true}}},
+	0x26: {Mnemonic: "LD", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "H", Immediate: true}, {Name: "n8", Bytes: 1, Immediate: true}}},
+	0x27: {Mnemonic: "DAA", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z-0C"},
+	0x28: {Mnemonic: "JR", Bytes: 2, Cycles: 3, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "Z", Immediate: true}, {Name: "e8", Bytes: 1, Immediate: true}}},
+	0x29: {Mnemonic: "ADD", Bytes: 1, Cycles: 2, CyclesNotTaken: 2, Flags: "-0HC", Operands: []Operand{{Name: "HL", Immediate: true}, {Name: "HL", Immediate: true}}},
+	0x2A: {Mnemonic: "LD", Bytes: 1, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "HL", Increment: true}}},
+	0x2B: {Mnemonic: "DEC", Bytes: 1, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "HL", Immediate: true}}},
+	0x2C: {Mnemonic: "INC", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z0H-", Operands: []Operand{{Name: "L", Immediate: true}}},
+	0x2D: {Mnemonic: "DEC", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z1H-", Operands: []Operand{{Name: "L", Immediate: true}}},
+	0x2E: {Mnemonic: "LD", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "L", Immediate: true}, {Name: "n8", Bytes: 1, Immediate: true}}},
+	0x2F: {Mnemonic: "CPL", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "-11-"},
+	0x30: {Mnemonic: "JR", Bytes: 2, Cycles: 3, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "NC", Immediate: true}, {Name: "e8", Bytes: 1, Immediate: true}}},
+	0x31: {Mnemonic: "LD", Bytes: 3, Cycles: 3, CyclesNotTaken: 3, Flags: "----", Operands: []Operand{{Name: "SP", Immediate: true}, {Name: "n16", Bytes: 2, Immediate: true}}},
+	0x32: {Mnemonic: "LD", Bytes: 1, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "HL", Decrement: true}, {Name: "A", Immediate: true}}},
+	0x33: {Mnemonic: "INC", Bytes: 1, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "SP", Immediate: true}}},
+	0x34: {Mnemonic: "INC", Bytes: 1, Cycles: 3, CyclesNotTaken: 3, Flags: "Z0H-", Operands: []Operand{{Name: "HL"}}},
+	0x35: {Mnemonic: "DEC", Bytes: 1, Cycles: 3, CyclesNotTaken: 3, Flags: "Z1H-", Operands: []Operand{{Name: "HL"}}},
+	0x36: {Mnemonic: "LD", Bytes: 2, Cycles: 3, CyclesNotTaken: 3, Flags: "----", Operands: []Operand{{Name: "HL"}, {Name: "n8", Bytes: 1, Immediate: true}}},
+	0x37: {Mnemonic: "SCF", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "-001"},
+	0x38: {Mnemonic: "JR", Bytes: 2, Cycles: 3, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "C", Immediate: true}, {Name: "e8", Bytes: 1, Immediate: true}}},
+	0x39: {Mnemonic: "ADD", Bytes: 1, Cycles: 2, CyclesNotTaken: 2, Flags: "-0HC", Operands: []Operand{{Name: "HL", Immediate: true}, {Name: "SP", Immediate: true}}},
+	0x3A: {Mnemonic: "LD", Bytes: 1, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "HL", Decrement: true}}},
+	0x3B: {Mnemonic: "DEC", Bytes: 1, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "SP", Immediate: true}}},
+	0x3C: {Mnemonic: "INC", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z0H-", Operands: []Operand{{Name: "A", Immediate: true}}},
+	0x3D: {Mnemonic: "DEC", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z1H-", Operands: []Operand{{Name: "A", Immediate: true}}},
+	0x3E: {Mnemonic: "LD", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "n8", Bytes: 1, Immediate: true}}},
+	0x3F: {Mnemonic: "CCF", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "-00C"},
+	0x40: {Mnemonic: "LD", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----", Operands: []Operand{{Name: "B", Immediate: true}, {Name: "B", Immediate: true}}},
+	0x41: {Mnemonic: "LD", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----", Operands: []Operand{{Name: "B", Immediate: true}, {Name: "C", Immediate: true}}},
+	0x42: {Mnemonic: "LD", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----", Operands: []Operand{{Name: "B", Immediate: true}, {Name: "D", Immediate: true}}},
+	0x43: {Mnemonic: "LD", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----", Operands: []Operand{{Name: "B", Immediate: true}, {Name: "E", Immediate: true}}},
+	0x44: {Mnemonic: "LD", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----", Operands: []Operand{{Name: "B", Immediate: true}, {Name: "H", Immediate: true}}},
+	0x45: {Mnemonic: "LD", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----", Operands: []Operand{{Name: "B", Immediate: true}, {Name: "L", Immediate: true}}},
+	0x46: {Mnemonic: "LD", Bytes: 1, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "B", Immediate: true}, {Name: "HL"}}},
+	0x47: {Mnemonic: "LD", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----", Operands: []Operand{{Name: "B", Immediate: true}, {Name: "A", Immediate: true}}},
+	0x48: {Mnemonic: "LD", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----", Operands: []Operand{{Name: "C", Immediate: true}, {Name: "B", Immediate: true}}},
+	0x49: {Mnemonic: "LD", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----", Operands: []Operand{{Name: "C", Immediate: true}, {Name: "C", Immediate: true}}},
+	0x4A: {Mnemonic: "LD", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----", Operands: []Operand{{Name: "C", Immediate: true}, {Name: "D", Immediate: true}}},
+	0x4B: {Mnemonic: "LD", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----", Operands: []Operand{{Name: "C", Immediate: true}, {Name: "E", Immediate: true}}},
+	0x4C: {Mnemonic: "LD", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----", Operands: []Operand{{Name: "C", Immediate: true}, {Name: "H", Immediate: true}}},
+	0x4D: {Mnemonic: "LD", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----", Operands: []Operand{{Name: "C", Immediate: true}, {Name: "L", Immediate: true}}},
+	0x4E: {Mnemonic: "LD", Bytes: 1, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "C", Immediate: true}, {Name: "HL"}}},
+	0x4F: {Mnemonic: "LD", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----", Operands: []Operand{{Name: "C", Immediate: true}, {Name: "A", Immediate: true}}},
+	0x50: {Mnemonic: "LD", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----", Operands: []Operand{{Name: "D", Immediate: true}, {Name: "B", Immediate: true}}},
+	0x51: {Mnemonic: "LD", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----", Operands: []Operand{{Name: "D", Immediate: true}, {Name: "C", Immediate: true}}},
+	0x52: {Mnemonic: "LD", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----", Operands: []Operand{{Name: "D", Immediate: true}, {Name: "D", Immediate: true}}},
+	0x53: {Mnemonic: "LD", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----", Operands: []Operand{{Name: "D", Immediate: true}, {Name: "E", Immediate: true}}},
+	0x54: {Mnemonic: "LD", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----", Operands: []Operand{{Name: "D", Immediate: true}, {Name: "H", Immediate: true}}},
+	0x55: {Mnemonic: "LD", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----", Operands: []Operand{{Name: "D", Immediate: true}, {Name: "L", Immediate: true}}},
+	0x56: {Mnemonic: "LD", Bytes: 1, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "D", Immediate: true}, {Name: "HL"}}},
+	0x57: {Mnemonic: "LD", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----", Operands: []Operand{{Name: "D", Immediate: true}, {Name: "A", Immediate: true}}},
+	0x58: {Mnemonic: "LD", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----", Operands: []Operand{{Name: "E", Immediate: true}, {Name: "B", Immediate: true}}},
+	0x59: {Mnemonic: "LD", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----", Operands: []Operand{{Name: "E", Immediate: true}, {Name: "C", Immediate: true}}},
+	0x5A: {Mnemonic: "LD", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----", Operands: []Operand{{Name: "E", Immediate: true}, {Name: "D", Immediate: true}}},
+	0x5B: {Mnemonic: "LD", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----", Operands: []Operand{{Name: "E", Immediate: true}, {Name: "E", Immediate: true}}},
+	0x5C: {Mnemonic: "LD", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----", Operands: []Operand{{Name: "E", Immediate: true}, {Name: "H", Immediate: true}}},
+	0x5D: {Mnemonic: "LD", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----", Operands: []Operand{{Name: "E", Immediate: true}, {Name: "L", Immediate: true}}},
+	0x5E: {Mnemonic: "LD", Bytes: 1, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "E", Immediate: true}, {Name: "HL"}}},
+	0x5F: {Mnemonic: "LD", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----", Operands: []Operand{{Name: "E", Immediate: true}, {Name: "A", Immediate: true}}},
+	0x60: {Mnemonic: "LD", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----", Operands: []Operand{{Name: "H", Immediate: true}, {Name: "B", Immediate: true}}},
+	0x61: {Mnemonic: "LD", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----", Operands: []Operand{{Name: "H", Immediate: true}, {Name: "C", Immediate: true}}},
+	0x62: {Mnemonic: "LD", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----", Operands: []Operand{{Name: "H", Immediate: true}, {Name: "D", Immediate: true}}},
+	0x63: {Mnemonic: "LD", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----", Operands: []Operand{{Name: "H", Immediate: true}, {Name: "E", Immediate: true}}},
+	0x64: {Mnemonic: "LD", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----", Operands: []Operand{{Name: "H", Immediate: true}, {Name: "H", Immediate: true}}},
+	0x65: {Mnemonic: "LD", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----", Operands: []Operand{{Name: "H", Immediate: true}, {Name: "L", Immediate: true}}},
+	0x66: {Mnemonic: "LD", Bytes: 1, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "H", Immediate: true}, {Name: "HL"}}},
+	0x67: {Mnemonic: "LD", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----", Operands: []Operand{{Name: "H", Immediate: true}, {Name: "A", Immediate: true}}},
+	0x68: {Mnemonic: "LD", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----", Operands: []Operand{{Name: "L", Immediate: true}, {Name: "B", Immediate: true}}},
+	0x69: {Mnemonic: "LD", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----", Operands: []Operand{{Name: "L", Immediate: true}, {Name: "C", Immediate: true}}},
+	0x6A: {Mnemonic: "LD", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----", Operands: []Operand{{Name: "L", Immediate: true}, {Name: "D", Immediate: true}}},
+	0x6B: {Mnemonic: "LD", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----", Operands: []Operand{{Name: "L", Immediate: true}, {Name: "E", Immediate: true}}},
+	0x6C: {Mnemonic: "LD", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----", Operands: []Operand{{Name: "L", Immediate: true}, {Name: "H", Immediate: true}}},
+	0x6D: {Mnemonic: "LD", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----", Operands: []Operand{{Name: "L", Immediate: true}, {Name: "L", Immediate: true}}},
+	0x6E: {Mnemonic: "LD", Bytes: 1, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "L", Immediate: true}, {Name: "HL"}}},
+	0x6F: {Mnemonic: "LD", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----", Operands: []Operand{{Name: "L", Immediate: true}, {Name: "A", Immediate: true}}},
+	0x70: {Mnemonic: "LD", Bytes: 1, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "HL"}, {Name: "B", Immediate: true}}},
+	0x71: {Mnemonic: "LD", Bytes: 1, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "HL"}, {Name: "C", Immediate: true}}},
+	0x72: {Mnemonic: "LD", Bytes: 1, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "HL"}, {Name: "D", Immediate: true}}},
+	0x73: {Mnemonic: "LD", Bytes: 1, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "HL"}, {Name: "E", Immediate: true}}},
+	0x74: {Mnemonic: "LD", Bytes: 1, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "HL"}, {Name: "H", Immediate: true}}},
+	0x75: {Mnemonic: "LD", Bytes: 1, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "HL"}, {Name: "L", Immediate: true}}},
+	0x76: {Mnemonic: "HALT", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----"},
+	0x77: {Mnemonic: "LD", Bytes: 1, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "HL"}, {Name: "A", Immediate: true}}},
+	0x78: {Mnemonic: "LD", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "B", Immediate: true}}},
+	0x79: {Mnemonic: "LD", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "C", Immediate: true}}},
+	0x7A: {Mnemonic: "LD", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "D", Immediate: true}}},
+	0x7B: {Mnemonic: "LD", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "E", Immediate: true}}},
+	0x7C: {Mnemonic: "LD", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "H", Immediate: true}}},
+	0x7D: {Mnemonic: "LD", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "L", Immediate: true}}},
+	0x7E: {Mnemonic: "LD", Bytes: 1, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "HL"}}},
+	0x7F: {Mnemonic: "LD", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "A", Immediate: true}}},
+	0x80: {Mnemonic: "ADD", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z0HC", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "B", Immediate: true}}},
+	0x81: {Mnemonic: "ADD", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z0HC", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "C", Immediate: true}}},
+	0x82: {Mnemonic: "ADD", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z0HC", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "D", Immediate: true}}},
+	0x83: {Mnemonic: "ADD", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z0HC", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "E", Immediate: true}}},
+	0x84: {Mnemonic: "ADD", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z0HC", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "H", Immediate: true}}},
+	0x85: {Mnemonic: "ADD", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z0HC", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "L", Immediate: true}}},
+	0x86: {Mnemonic: "ADD", Bytes: 1, Cycles: 2, CyclesNotTaken: 2, Flags: "Z0HC", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "HL"}}},
+	0x87: {Mnemonic: "ADD", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z0HC", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "A", Immediate: true}}},
+	0x88: {Mnemonic: "ADC", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z0HC", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "B", Immediate: true}}},
+	0x89: {Mnemonic: "ADC", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z0HC", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "C", Immediate: true}}},
+	0x8A: {Mnemonic: "ADC", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z0HC", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "D", Immediate: true}}},
+	0x8B: {Mnemonic: "ADC", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z0HC", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "E", Immediate: true}}},
+	0x8C: {Mnemonic: "ADC", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z0HC", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "H", Immediate: true}}},
+	0x8D: {Mnemonic: "ADC", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z0HC", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "L", Immediate: true}}},
+	0x8E: {Mnemonic: "ADC", Bytes: 1, Cycles: 2, CyclesNotTaken: 2, Flags: "Z0HC", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "HL"}}},
+	0x8F: {Mnemonic: "ADC", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z0HC", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "A", Immediate: true}}},
+	0x90: {Mnemonic: "SUB", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z1HC", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "B", Immediate: true}}},
+	0x91: {Mnemonic: "SUB", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z1HC", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "C", Immediate: true}}},
+	0x92: {Mnemonic: "SUB", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z1HC", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "D", Immediate: true}}},
+	0x93: {Mnemonic: "SUB", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z1HC", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "E", Immediate: true}}},
+	0x94: {Mnemonic: "SUB", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z1HC", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "H", Immediate: true}}},
+	0x95: {Mnemonic: "SUB", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z1HC", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "L", Immediate: true}}},
+	0x96: {Mnemonic: "SUB", Bytes: 1, Cycles: 2, CyclesNotTaken: 2, Flags: "Z1HC", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "HL"}}},
+	0x97: {Mnemonic: "SUB", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z1HC", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "A", Immediate: true}}},
+	0x98: {Mnemonic: "SBC", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z1HC", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "B", Immediate: true}}},
+	0x99: {Mnemonic: "SBC", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z1HC", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "C", Immediate: true}}},
+	0x9A: {Mnemonic: "SBC", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z1HC", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "D", Immediate: true}}},
+	0x9B: {Mnemonic: "SBC", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z1HC", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "E", Immediate: true}}},
+	0x9C: {Mnemonic: "SBC", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z1HC", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "H", Immediate: true}}},
+	0x9D: {Mnemonic: "SBC", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z1HC", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "L", Immediate: true}}},
+	0x9E: {Mnemonic: "SBC", Bytes: 1, Cycles: 2, CyclesNotTaken: 2, Flags: "Z1HC", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "HL"}}},
+	0x9F: {Mnemonic: "SBC", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z1HC", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "A", Immediate: true}}},
+	0xA0: {Mnemonic: "AND", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z010", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "B", Immediate: true}}},
+	0xA1: {Mnemonic: "AND", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z010", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "C", Immediate: true}}},
+	0xA2: {Mnemonic: "AND", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z010", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "D", Immediate: true}}},
+	0xA3: {Mnemonic: "AND", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z010", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "E", Immediate: true}}},
+	0xA4: {Mnemonic: "AND", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z010", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "H", Immediate: true}}},
+	0xA5: {Mnemonic: "AND", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z010", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "L", Immediate: true}}},
+	0xA6: {Mnemonic: "AND", Bytes: 1, Cycles: 2, CyclesNotTaken: 2, Flags: "Z010", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "HL"}}},
+	0xA7: {Mnemonic: "AND", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z010", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "A", Immediate: true}}},
+	0xA8: {Mnemonic: "XOR", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z000", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "B", Immediate: true}}},
+	0xA9: {Mnemonic: "XOR", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z000", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "C", Immediate: true}}},
+	0xAA: {Mnemonic: "XOR", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z000", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "D", Immediate: true}}},
+	0xAB: {Mnemonic: "XOR", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z000", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "E", Immediate: true}}},
+	0xAC: {Mnemonic: "XOR", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z000", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "H", Immediate: true}}},
+	0xAD: {Mnemonic: "XOR", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z000", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "L", Immediate: true}}},
+	0xAE: {Mnemonic: "XOR", Bytes: 1, Cycles: 2, CyclesNotTaken: 2, Flags: "Z000", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "HL"}}},
+	0xAF: {Mnemonic: "XOR", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z000", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "A", Immediate: true}}},
+	0xB0: {Mnemonic: "OR", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z000", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "B", Immediate: true}}},
+	0xB1: {Mnemonic: "OR", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z000", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "C", Immediate: true}}},
+	0xB2: {Mnemonic: "OR", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z000", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "D", Immediate: true}}},
+	0xB3: {Mnemonic: "OR", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z000", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "E", Immediate: true}}},
+	0xB4: {Mnemonic: "OR", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z000", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "H", Immediate: true}}},
+	0xB5: {Mnemonic: "OR", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z000", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "L", Immediate: true}}},
+	0xB6: {Mnemonic: "OR", Bytes: 1, Cycles: 2, CyclesNotTaken: 2, Flags: "Z000", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "HL"}}},
+	0xB7: {Mnemonic: "OR", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z000", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "A", Immediate: true}}},
+	0xB8: {Mnemonic: "CP", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z1HC", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "B", Immediate: true}}},
+	0xB9: {Mnemonic: "CP", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z1HC", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "C", Immediate: true}}},
+	0xBA: {Mnemonic: "CP", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z1HC", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "D", Immediate: true}}},
+	0xBB: {Mnemonic: "CP", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z1HC", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "E", Immediate: true}}},
+	0xBC: {Mnemonic: "CP", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z1HC", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "H", Immediate: true}}},
+	0xBD: {Mnemonic: "CP", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z1HC", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "L", Immediate: true}}},
+	0xBE: {Mnemonic: "CP", Bytes: 1, Cycles: 2, CyclesNotTaken: 2, Flags: "Z1HC", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "HL"}}},
+	0xBF: {Mnemonic: "CP", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "Z1HC", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "A", Immediate: true}}},
+	0xC0: {Mnemonic: "RET", Bytes: 1, Cycles: 5, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "NZ", Immediate: true}}},
+	0xC1: {Mnemonic: "POP", Bytes: 1, Cycles: 3, CyclesNotTaken: 3, Flags: "----", Operands: []Operand{{Name: "BC", Immediate: true}}},
+	0xC2: {Mnemonic: "JP", Bytes: 3, Cycles: 4, CyclesNotTaken: 3, Flags: "----", Operands: []Operand{{Name: "NZ", Immediate: true}, {Name: "a16", Bytes: 2, Immediate: true}}},
+	0xC3: {Mnemonic: "JP", Bytes: 3, Cycles: 4, CyclesNotTaken: 4, Flags: "----", Operands: []Operand{{Name: "a16", Bytes: 2, Immediate: true}}},
+	0xC4: {Mnemonic: "CALL", Bytes: 3, Cycles: 6, CyclesNotTaken: 3, Flags: "----", Operands: []Operand{{Name: "NZ", Immediate: true}, {Name: "a16", Bytes: 2, Immediate: true}}},
+	0xC5: {Mnemonic: "PUSH", Bytes: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----", Operands: []Operand{{Name: "BC", Immediate: true}}},
+	0xC6: {Mnemonic: "ADD", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z0HC", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "n8", Bytes: 1, Immediate: true}}},
+	0xC7: {Mnemonic: "RST", Bytes: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----", Operands: []Operand{{Name: "$00", Immediate: true}}},
+	0xC8: {Mnemonic: "RET", Bytes: 1, Cycles: 5, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "Z", Immediate: true}}},
+	0xC9: {Mnemonic: "RET", Bytes: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0xCA: {Mnemonic: "JP", Bytes: 3, Cycles: 4, CyclesNotTaken: 3, Flags: "----", Operands: []Operand{{Name: "Z", Immediate: true}, {Name: "a16", Bytes: 2, Immediate: true}}},
+	0xCB: {Mnemonic: "PREFIX", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----"},
+	0xCC: {Mnemonic: "CALL", Bytes: 3, Cycles: 6, CyclesNotTaken: 3, Flags: "----", Operands: []Operand{{Name: "Z", Immediate: true}, {Name: "a16", Bytes: 2, Immediate: true}}},
+	0xCD: {Mnemonic: "CALL", Bytes: 3, Cycles: 6, CyclesNotTaken: 6, Flags: "----", Operands: []Operand{{Name: "a16", Bytes: 2, Immediate: true}}},
+	0xCE: {Mnemonic: "ADC", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z0HC", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "n8", Bytes: 1, Immediate: true}}},
+	0xCF: {Mnemonic: "RST", Bytes: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----", Operands: []Operand{{Name: "$08", Immediate: true}}},
+	0xD0: {Mnemonic: "RET", Bytes: 1, Cycles: 5, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "NC", Immediate: true}}},
+	0xD1: {Mnemonic: "POP", Bytes: 1, Cycles: 3, CyclesNotTaken: 3, Flags: "----", Operands: []Operand{{Name: "DE", Immediate: true}}},
+	0xD2: {Mnemonic: "JP", Bytes: 3, Cycles: 4, CyclesNotTaken: 3, Flags: "----", Operands: []Operand{{Name: "NC", Immediate: true}, {Name: "a16", Bytes: 2, Immediate: true}}},
+	0xD3: {Mnemonic: "ILLEGAL_D3", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----"},
+	0xD4: {Mnemonic: "CALL", Bytes: 3, Cycles: 6, CyclesNotTaken: 3, Flags: "----", Operands: []Operand{{Name: "NC", Immediate: true}, {Name: "a16", Bytes: 2, Immediate: true}}},
+	0xD5: {Mnemonic: "PUSH", Bytes: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----", Operands: []Operand{{Name: "DE", Immediate: true}}},
+	0xD6: {Mnemonic: "SUB", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z1HC", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "n8", Bytes: 1, Immediate: true}}},
+	0xD7: {Mnemonic: "RST", Bytes: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----", Operands: []Operand{{Name: "$10", Immediate: true}}},
+	0xD8: {Mnemonic: "RET", Bytes: 1, Cycles: 5, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "C", Immediate: true}}},
+	0xD9: {Mnemonic: "RETI", Bytes: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----"},
+	0xDA: {Mnemonic: "JP", Bytes: 3, Cycles: 4, CyclesNotTaken: 3, Flags: "----", Operands: []Operand{{Name: "C", Immediate: true}, {Name: "a16", Bytes: 2, Immediate: true}}},
+	0xDB: {Mnemonic: "ILLEGAL_DB", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----"},
+	0xDC: {Mnemonic: "CALL", Bytes: 3, Cycles: 6, CyclesNotTaken: 3, Flags: "----", Operands: []Operand{{Name: "C", Immediate: true}, {Name: "a16", Bytes: 2, Immediate: true}}},
+	0xDD: {Mnemonic: "ILLEGAL_DD", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----"},
+	0xDE: {Mnemonic: "SBC", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z1HC", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "n8", Bytes: 1, Immediate: true}}},
+	0xDF: {Mnemonic: "RST", Bytes: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----", Operands: []Operand{{Name: "$18", Immediate: true}}},
+	0xE0: {Mnemonic: "LDH", Bytes: 2, Cycles: 3, CyclesNotTaken: 3, Flags: "----", Operands: []Operand{{Name: "a8", Bytes: 1}, {Name: "A", Immediate: true}}},
+	0xE1: {Mnemonic: "POP", Bytes: 1, Cycles: 3, CyclesNotTaken: 3, Flags: "----", Operands: []Operand{{Name: "HL", Immediate: true}}},
+	0xE2: {Mnemonic: "LD", Bytes: 1, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "C"}, {Name: "A", Immediate: true}}},
+	0xE3: {Mnemonic: "ILLEGAL_E3", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----"},
+	0xE4: {Mnemonic: "ILLEGAL_E4", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----"},
+	0xE5: {Mnemonic: "PUSH", Bytes: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----", Operands: []Operand{{Name: "HL", Immediate: true}}},
+	0xE6: {Mnemonic: "AND", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z010", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "n8", Bytes: 1, Immediate: true}}},
+	0xE7: {Mnemonic: "RST", Bytes: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----", Operands: []Operand{{Name: "$20", Immediate: true}}},
+	0xE8: {Mnemonic: "ADD", Bytes: 2, Cycles: 4, CyclesNotTaken: 4, Flags: "00HC", Operands: []Operand{{Name: "SP", Immediate: true}, {Name: "e8", Bytes: 1, Immediate: true}}},
+	0xE9: {Mnemonic: "JP", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----", Operands: []Operand{{Name: "HL", Immediate: true}}},
+	0xEA: {Mnemonic: "LD", Bytes: 3, Cycles: 4, CyclesNotTaken: 4, Flags: "----", Operands: []Operand{{Name: "a16", Bytes: 2}, {Name: "A", Immediate: true}}},
+	0xEB: {Mnemonic: "ILLEGAL_EB", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----"},
+	0xEC: {Mnemonic: "ILLEGAL_EC", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----"},
+	0xED: {Mnemonic: "ILLEGAL_ED", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----"},
+	0xEE: {Mnemonic: "XOR", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z000", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "n8", Bytes: 1, Immediate: true}}},
+	0xEF: {Mnemonic: "RST", Bytes: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----", Operands: []Operand{{Name: "$28", Immediate: true}}},
+	0xF0: {Mnemonic: "LDH", Bytes: 2, Cycles: 3, CyclesNotTaken: 3, Flags: "----", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "a8", Bytes: 1}}},
+	0xF1: {Mnemonic: "POP", Bytes: 1, Cycles: 3, CyclesNotTaken: 3, Flags: "ZNHC", Operands: []Operand{{Name: "AF", Immediate: true}}},
+	0xF2: {Mnemonic: "LD", Bytes: 1, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "C"}}},
+	0xF3: {Mnemonic: "DI", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----"},
+	0xF4: {Mnemonic: "ILLEGAL_F4", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----"},
+	0xF5: {Mnemonic: "PUSH", Bytes: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----", Operands: []Operand{{Name: "AF", Immediate: true}}},
+	0xF6: {Mnemonic: "OR", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z000", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "n8", Bytes: 1, Immediate: true}}},
+	0xF7: {Mnemonic: "RST", Bytes: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----", Operands: []Operand{{Name: "$30", Immediate: true}}},
+	0xF8: {Mnemonic: "LD", Bytes: 2, Cycles: 3, CyclesNotTaken: 3, Flags: "00HC", Operands: []Operand{{Name: "HL", Immediate: true}, {Name: "SP", Immediate: true, Increment: true}, {Name: "e8", Bytes: 1, Immediate: true}}},
+	0xF9: {Mnemonic: "LD", Bytes: 1, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "SP", Immediate: true}, {Name: "HL", Immediate: true}}},
+	0xFA: {Mnemonic: "LD", Bytes: 3, Cycles: 4, CyclesNotTaken: 4, Flags: "----", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "a16", Bytes: 2}}},
+	0xFB: {Mnemonic: "EI", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----"},
+	0xFC: {Mnemonic: "ILLEGAL_FC", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----"},
+	0xFD: {Mnemonic: "ILLEGAL_FD", Bytes: 1, Cycles: 1, CyclesNotTaken: 1, Flags: "----"},
+	0xFE: {Mnemonic: "CP", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z1HC", Operands: []Operand{{Name: "A", Immediate: true}, {Name: "n8", Bytes: 1, Immediate: true}}},
+	0xFF: {Mnemonic: "RST", Bytes: 1, Cycles: 4, CyclesNotTaken: 4, Flags: "----", Operands: []Operand{{Name: "$38", Immediate: true}}},
+}
+
+// CBOpcodes describes each opcode following the 0xCB prefix.
+var CBOpcodes = [0x100]Opcode{
+	0x00: {Mnemonic: "RLC", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z00C", Operands: []Operand{{Name: "B", Immediate: true}}},
+	0x01: {Mnemonic: "RLC", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z00C", Operands: []Operand{{Name: "C", Immediate: true}}},
+	0x02: {Mnemonic: "RLC", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z00C", Operands: []Operand{{Name: "D", Immediate: true}}},
+	0x03: {Mnemonic: "RLC", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z00C", Operands: []Operand{{Name: "E", Immediate: true}}},
+	0x04: {Mnemonic: "RLC", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z00C", Operands: []Operand{{Name: "H", Immediate: true}}},
+	0x05: {Mnemonic: "RLC", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z00C", Operands: []Operand{{Name: "L", Immediate: true}}},
+	0x06: {Mnemonic: "RLC", Bytes: 2, Cycles: 4, CyclesNotTaken: 4, Flags: "Z00C", Operands: []Operand{{Name: "HL"}}},
+	0x07: {Mnemonic: "RLC", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z00C", Operands: []Operand{{Name: "A", Immediate: true}}},
+	0x08: {Mnemonic: "RRC", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z00C", Operands: []Operand{{Name: "B", Immediate: true}}},
+	0x09: {Mnemonic: "RRC", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z00C", Operands: []Operand{{Name: "C", Immediate: true}}},
+	0x0A: {Mnemonic: "RRC", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z00C", Operands: []Operand{{Name: "D", Immediate: true}}},
+	0x0B: {Mnemonic: "RRC", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z00C", Operands: []Operand{{Name: "E", Immediate: true}}},
+	0x0C: {Mnemonic: "RRC", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z00C", Operands: []Operand{{Name: "H", Immediate: true}}},
+	0x0D: {Mnemonic: "RRC", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z00C", Operands: []Operand{{Name: "L", Immediate: true}}},
+	0x0E: {Mnemonic: "RRC", Bytes: 2, Cycles: 4, CyclesNotTaken: 4, Flags: "Z00C", Operands: []Operand{{Name: "HL"}}},
+	0x0F: {Mnemonic: "RRC", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z00C", Operands: []Operand{{Name: "A", Immediate: true}}},
+	0x10: {Mnemonic: "RL", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z00C", Operands: []Operand{{Name: "B", Immediate: true}}},
+	0x11: {Mnemonic: "RL", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z00C", Operands: []Operand{{Name: "C", Immediate: true}}},
+	0x12: {Mnemonic: "RL", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z00C", Operands: []Operand{{Name: "D", Immediate: true}}},
+	0x13: {Mnemonic: "RL", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z00C", Operands: []Operand{{Name: "E", Immediate: true}}},
+	0x14: {Mnemonic: "RL", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z00C", Operands: []Operand{{Name: "H", Immediate: true}}},
+	0x15: {Mnemonic: "RL", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z00C", Operands: []Operand{{Name: "L", Immediate: true}}},
+	0x16: {Mnemonic: "RL", Bytes: 2, Cycles: 4, CyclesNotTaken: 4, Flags: "Z00C", Operands: []Operand{{Name: "HL"}}},
+	0x17: {Mnemonic: "RL", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z00C", Operands: []Operand{{Name: "A", Immediate: true}}},
+	0x18: {Mnemonic: "RR", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z00C", Operands: []Operand{{Name: "B", Immediate: true}}},
+	0x19: {Mnemonic: "RR", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z00C", Operands: []Operand{{Name: "C", Immediate: true}}},
+	0x1A: {Mnemonic: "RR", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z00C", Operands: []Operand{{Name: "D", Immediate: true}}},
+	0x1B: {Mnemonic: "RR", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z00C", Operands: []Operand{{Name: "E", Immediate: true}}},
+	0x1C: {Mnemonic: "RR", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z00C", Operands: []Operand{{Name: "H", Immediate: true}}},
+	0x1D: {Mnemonic: "RR", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z00C", Operands: []Operand{{Name: "L", Immediate: true}}},
+	0x1E: {Mnemonic: "RR", Bytes: 2, Cycles: 4, CyclesNotTaken: 4, Flags: "Z00C", Operands: []Operand{{Name: "HL"}}},
+	0x1F: {Mnemonic: "RR", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z00C", Operands: []Operand{{Name: "A", Immediate: true}}},
+	0x20: {Mnemonic: "SLA", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z00C", Operands: []Operand{{Name: "B", Immediate: true}}},
+	0x21: {Mnemonic: "SLA", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z00C", Operands: []Operand{{Name: "C", Immediate: true}}},
+	0x22: {Mnemonic: "SLA", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z00C", Operands: []Operand{{Name: "D", Immediate: true}}},
+	0x23: {Mnemonic: "SLA", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z00C", Operands: []Operand{{Name: "E", Immediate: true}}},
+	0x24: {Mnemonic: "SLA", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z00C", Operands: []Operand{{Name: "H", Immediate: true}}},
+	0x25: {Mnemonic: "SLA", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z00C", Operands: []Operand{{Name: "L", Immediate: true}}},
+	0x26: {Mnemonic: "SLA", Bytes: 2, Cycles: 4, CyclesNotTaken: 4, Flags: "Z00C", Operands: []Operand{{Name: "HL"}}},
+	0x27: {Mnemonic: "SLA", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z00C", Operands: []Operand{{Name: "A", Immediate: true}}},
+	0x28: {Mnemonic: "SRA", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z00C", Operands: []Operand{{Name: "B", Immediate: true}}},
+	0x29: {Mnemonic: "SRA", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z00C", Operands: []Operand{{Name: "C", Immediate: true}}},
+	0x2A: {Mnemonic: "SRA", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z00C", Operands: []Operand{{Name: "D", Immediate: true}}},
+	0x2B: {Mnemonic: "SRA", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z00C", Operands: []Operand{{Name: "E", Immediate: true}}},
+	0x2C: {Mnemonic: "SRA", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z00C", Operands: []Operand{{Name: "H", Immediate: true}}},
+	0x2D: {Mnemonic: "SRA", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z00C", Operands: []Operand{{Name: "L", Immediate: true}}},
+	0x2E: {Mnemonic: "SRA", Bytes: 2, Cycles: 4, CyclesNotTaken: 4, Flags: "Z00C", Operands: []Operand{{Name: "HL"}}},
+	0x2F: {Mnemonic: "SRA", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z00C", Operands: []Operand{{Name: "A", Immediate: true}}},
+	0x30: {Mnemonic: "SWAP", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z000", Operands: []Operand{{Name: "B", Immediate: true}}},
+	0x31: {Mnemonic: "SWAP", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z000", Operands: []Operand{{Name: "C", Immediate: true}}},
+	0x32: {Mnemonic: "SWAP", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z000", Operands: []Operand{{Name: "D", Immediate: true}}},
+	0x33: {Mnemonic: "SWAP", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z000", Operands: []Operand{{Name: "E", Immediate: true}}},
+	0x34: {Mnemonic: "SWAP", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z000", Operands: []Operand{{Name: "H", Immediate: true}}},
+	0x35: {Mnemonic: "SWAP", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z000", Operands: []Operand{{Name: "L", Immediate: true}}},
+	0x36: {Mnemonic: "SWAP", Bytes: 2, Cycles: 4, CyclesNotTaken: 4, Flags: "Z000", Operands: []Operand{{Name: "HL"}}},
+	0x37: {Mnemonic: "SWAP", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z000", Operands: []Operand{{Name: "A", Immediate: true}}},
+	0x38: {Mnemonic: "SRL", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z00C", Operands: []Operand{{Name: "B", Immediate: true}}},
+	0x39: {Mnemonic: "SRL", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z00C", Operands: []Operand{{Name: "C", Immediate: true}}},
+	0x3A: {Mnemonic: "SRL", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z00C", Operands: []Operand{{Name: "D", Immediate: true}}},
+	0x3B: {Mnemonic: "SRL", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z00C", Operands: []Operand{{Name: "E", Immediate: true}}},
+	0x3C: {Mnemonic: "SRL", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z00C", Operands: []Operand{{Name: "H", Immediate: true}}},
+	0x3D: {Mnemonic: "SRL", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z00C", Operands: []Operand{{Name: "L", Immediate: true}}},
+	0x3E: {Mnemonic: "SRL", Bytes: 2, Cycles: 4, CyclesNotTaken: 4, Flags: "Z00C", Operands: []Operand{{Name: "HL"}}},
+	0x3F: {Mnemonic: "SRL", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z00C", Operands: []Operand{{Name: "A", Immediate: true}}},
+	0x40: {Mnemonic: "BIT", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z01-", Operands: []Operand{{Name: "0", Immediate: true}, {Name: "B", Immediate: true}}},
+	0x41: {Mnemonic: "BIT", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z01-", Operands: []Operand{{Name: "0", Immediate: true}, {Name: "C", Immediate: true}}},
+	0x42: {Mnemonic: "BIT", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z01-", Operands: []Operand{{Name: "0", Immediate: true}, {Name: "D", Immediate: true}}},
+	0x43: {Mnemonic: "BIT", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z01-", Operands: []Operand{{Name: "0", Immediate: true}, {Name: "E", Immediate: true}}},
+	0x44: {Mnemonic: "BIT", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z01-", Operands: []Operand{{Name: "0", Immediate: true}, {Name: "H", Immediate: true}}},
+	0x45: {Mnemonic: "BIT", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z01-", Operands: []Operand{{Name: "0", Immediate: true}, {Name: "L", Immediate: true}}},
+	0x46: {Mnemonic: "BIT", Bytes: 2, Cycles: 3, CyclesNotTaken: 3, Flags: "Z01-", Operands: []Operand{{Name: "0", Immediate: true}, {Name: "HL"}}},
+	0x47: {Mnemonic: "BIT", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z01-", Operands: []Operand{{Name: "0", Immediate: true}, {Name: "A", Immediate: true}}},
+	0x48: {Mnemonic: "BIT", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z01-", Operands: []Operand{{Name: "1", Immediate: true}, {Name: "B", Immediate: true}}},
+	0x49: {Mnemonic: "BIT", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z01-", Operands: []Operand{{Name: "1", Immediate: true}, {Name: "C", Immediate: true}}},
+	0x4A: {Mnemonic: "BIT", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z01-", Operands: []Operand{{Name: "1", Immediate: true}, {Name: "D", Immediate: true}}},
+	0x4B: {Mnemonic: "BIT", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z01-", Operands: []Operand{{Name: "1", Immediate: true}, {Name: "E", Immediate: true}}},
+	0x4C: {Mnemonic: "BIT", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z01-", Operands: []Operand{{Name: "1", Immediate: true}, {Name: "H", Immediate: true}}},
+	0x4D: {Mnemonic: "BIT", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z01-", Operands: []Operand{{Name: "1", Immediate: true}, {Name: "L", Immediate: true}}},
+	0x4E: {Mnemonic: "BIT", Bytes: 2, Cycles: 3, CyclesNotTaken: 3, Flags: "Z01-", Operands: []Operand{{Name: "1", Immediate: true}, {Name: "HL"}}},
+	0x4F: {Mnemonic: "BIT", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z01-", Operands: []Operand{{Name: "1", Immediate: true}, {Name: "A", Immediate: true}}},
+	0x50: {Mnemonic: "BIT", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z01-", Operands: []Operand{{Name: "2", Immediate: true}, {Name: "B", Immediate: true}}},
+	0x51: {Mnemonic: "BIT", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z01-", Operands: []Operand{{Name: "2", Immediate: true}, {Name: "C", Immediate: true}}},
+	0x52: {Mnemonic: "BIT", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z01-", Operands: []Operand{{Name: "2", Immediate: true}, {Name: "D", Immediate: true}}},
+	0x53: {Mnemonic: "BIT", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z01-", Operands: []Operand{{Name: "2", Immediate: true}, {Name: "E", Immediate: true}}},
+	0x54: {Mnemonic: "BIT", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z01-", Operands: []Operand{{Name: "2", Immediate: true}, {Name: "H", Immediate: true}}},
+	0x55: {Mnemonic: "BIT", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z01-", Operands: []Operand{{Name: "2", Immediate: true}, {Name: "L", Immediate: true}}},
+	0x56: {Mnemonic: "BIT", Bytes: 2, Cycles: 3, CyclesNotTaken: 3, Flags: "Z01-", Operands: []Operand{{Name: "2", Immediate: true}, {Name: "HL"}}},
+	0x57: {Mnemonic: "BIT", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z01-", Operands: []Operand{{Name: "2", Immediate: true}, {Name: "A", Immediate: true}}},
+	0x58: {Mnemonic: "BIT", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z01-", Operands: []Operand{{Name: "3", Immediate: true}, {Name: "B", Immediate: true}}},
+	0x59: {Mnemonic: "BIT", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z01-", Operands: []Operand{{Name: "3", Immediate: true}, {Name: "C", Immediate: true}}},
+	0x5A: {Mnemonic: "BIT", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z01-", Operands: []Operand{{Name: "3", Immediate: true}, {Name: "D", Immediate: true}}},
+	0x5B: {Mnemonic: "BIT", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z01-", Operands: []Operand{{Name: "3", Immediate: true}, {Name: "E", Immediate: true}}},
+	0x5C: {Mnemonic: "BIT", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z01-", Operands: []Operand{{Name: "3", Immediate: true}, {Name: "H", Immediate: true}}},
+	0x5D: {Mnemonic: "BIT", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z01-", Operands: []Operand{{Name: "3", Immediate: true}, {Name: "L", Immediate: true}}},
+	0x5E: {Mnemonic: "BIT", Bytes: 2, Cycles: 3, CyclesNotTaken: 3, Flags: "Z01-", Operands: []Operand{{Name: "3", Immediate: true}, {Name: "HL"}}},
+	0x5F: {Mnemonic: "BIT", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z01-", Operands: []Operand{{Name: "3", Immediate: true}, {Name: "A", Immediate: true}}},
+	0x60: {Mnemonic: "BIT", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z01-", Operands: []Operand{{Name: "4", Immediate: true}, {Name: "B", Immediate: true}}},
+	0x61: {Mnemonic: "BIT", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z01-", Operands: []Operand{{Name: "4", Immediate: true}, {Name: "C", Immediate: true}}},
+	0x62: {Mnemonic: "BIT", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z01-", Operands: []Operand{{Name: "4", Immediate: true}, {Name: "D", Immediate: true}}},
+	0x63: {Mnemonic: "BIT", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z01-", Operands: []Operand{{Name: "4", Immediate: true}, {Name: "E", Immediate: true}}},
+	0x64: {Mnemonic: "BIT", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z01-", Operands: []Operand{{Name: "4", Immediate: true}, {Name: "H", Immediate: true}}},
+	0x65: {Mnemonic: "BIT", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z01-", Operands: []Operand{{Name: "4", Immediate: true}, {Name: "L", Immediate: true}}},
+	0x66: {Mnemonic: "BIT", Bytes: 2, Cycles: 3, CyclesNotTaken: 3, Flags: "Z01-", Operands: []Operand{{Name: "4", Immediate: true}, {Name: "HL"}}},
+	0x67: {Mnemonic: "BIT", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z01-", Operands: []Operand{{Name: "4", Immediate: true}, {Name: "A", Immediate: true}}},
+	0x68: {Mnemonic: "BIT", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z01-", Operands: []Operand{{Name: "5", Immediate: true}, {Name: "B", Immediate: true}}},
+	0x69: {Mnemonic: "BIT", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z01-", Operands: []Operand{{Name: "5", Immediate: true}, {Name: "C", Immediate: true}}},
+	0x6A: {Mnemonic: "BIT", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z01-", Operands: []Operand{{Name: "5", Immediate: true}, {Name: "D", Immediate: true}}},
+	0x6B: {Mnemonic: "BIT", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z01-", Operands: []Operand{{Name: "5", Immediate: true}, {Name: "E", Immediate: true}}},
+	0x6C: {Mnemonic: "BIT", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z01-", Operands: []Operand{{Name: "5", Immediate: true}, {Name: "H", Immediate: true}}},
+	0x6D: {Mnemonic: "BIT", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z01-", Operands: []Operand{{Name: "5", Immediate: true}, {Name: "L", Immediate: true}}},
+	0x6E: {Mnemonic: "BIT", Bytes: 2, Cycles: 3, CyclesNotTaken: 3, Flags: "Z01-", Operands: []Operand{{Name: "5", Immediate: true}, {Name: "HL"}}},
+	0x6F: {Mnemonic: "BIT", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z01-", Operands: []Operand{{Name: "5", Immediate: true}, {Name: "A", Immediate: true}}},
+	0x70: {Mnemonic: "BIT", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z01-", Operands: []Operand{{Name: "6", Immediate: true}, {Name: "B", Immediate: true}}},
+	0x71: {Mnemonic: "BIT", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z01-", Operands: []Operand{{Name: "6", Immediate: true}, {Name: "C", Immediate: true}}},
+	0x72: {Mnemonic: "BIT", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z01-", Operands: []Operand{{Name: "6", Immediate: true}, {Name: "D", Immediate: true}}},
+	0x73: {Mnemonic: "BIT", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z01-", Operands: []Operand{{Name: "6", Immediate: true}, {Name: "E", Immediate: true}}},
+	0x74: {Mnemonic: "BIT", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z01-", Operands: []Operand{{Name: "6", Immediate: true}, {Name: "H", Immediate: true}}},
+	0x75: {Mnemonic: "BIT", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z01-", Operands: []Operand{{Name: "6", Immediate: true}, {Name: "L", Immediate: true}}},
+	0x76: {Mnemonic: "BIT", Bytes: 2, Cycles: 3, CyclesNotTaken: 3, Flags: "Z01-", Operands: []Operand{{Name: "6", Immediate: true}, {Name: "HL"}}},
+	0x77: {Mnemonic: "BIT", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z01-", Operands: []Operand{{Name: "6", Immediate: true}, {Name: "A", Immediate: true}}},
+	0x78: {Mnemonic: "BIT", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z01-", Operands: []Operand{{Name: "7", Immediate: true}, {Name: "B", Immediate: true}}},
+	0x79: {Mnemonic: "BIT", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z01-", Operands: []Operand{{Name: "7", Immediate: true}, {Name: "C", Immediate: true}}},
+	0x7A: {Mnemonic: "BIT", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z01-", Operands: []Operand{{Name: "7", Immediate: true}, {Name: "D", Immediate: true}}},
+	0x7B: {Mnemonic: "BIT", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z01-", Operands: []Operand{{Name: "7", Immediate: true}, {Name: "E", Immediate: true}}},
+	0x7C: {Mnemonic: "BIT", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z01-", Operands: []Operand{{Name: "7", Immediate: true}, {Name: "H", Immediate: true}}},
+	0x7D: {Mnemonic: "BIT", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z01-", Operands: []Operand{{Name: "7", Immediate: true}, {Name: "L", Immediate: true}}},
+	0x7E: {Mnemonic: "BIT", Bytes: 2, Cycles: 3, CyclesNotTaken: 3, Flags: "Z01-", Operands: []Operand{{Name: "7", Immediate: true}, {Name: "HL"}}},
+	0x7F: {Mnemonic: "BIT", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "Z01-", Operands: []Operand{{Name: "7", Immediate: true}, {Name: "A", Immediate: true}}},
+	0x80: {Mnemonic: "RES", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "0", Immediate: true}, {Name: "B", Immediate: true}}},
+	0x81: {Mnemonic: "RES", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "0", Immediate: true}, {Name: "C", Immediate: true}}},
+	0x82: {Mnemonic: "RES", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "0", Immediate: true}, {Name: "D", Immediate: true}}},
+	0x83: {Mnemonic: "RES", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "0", Immediate: true}, {Name: "E", Immediate: true}}},
+	0x84: {Mnemonic: "RES", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "0", Immediate: true}, {Name: "H", Immediate: true}}},
+	0x85: {Mnemonic: "RES", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "0", Immediate: true}, {Name: "L", Immediate: true}}},
+	0x86: {Mnemonic: "RES", Bytes: 2, Cycles: 4, CyclesNotTaken: 4, Flags: "----", Operands: []Operand{{Name: "0", Immediate: true}, {Name: "HL"}}},
+	0x87: {Mnemonic: "RES", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "0", Immediate: true}, {Name: "A", Immediate: true}}},
+	0x88: {Mnemonic: "RES", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "1", Immediate: true}, {Name: "B", Immediate: true}}},
+	0x89: {Mnemonic: "RES", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "1", Immediate: true}, {Name: "C", Immediate: true}}},
+	0x8A: {Mnemonic: "RES", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "1", Immediate: true}, {Name: "D", Immediate: true}}},
+	0x8B: {Mnemonic: "RES", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "1", Immediate: true}, {Name: "E", Immediate: true}}},
+	0x8C: {Mnemonic: "RES", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "1", Immediate: true}, {Name: "H", Immediate: true}}},
+	0x8D: {Mnemonic: "RES", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "1", Immediate: true}, {Name: "L", Immediate: true}}},
+	0x8E: {Mnemonic: "RES", Bytes: 2, Cycles: 4, CyclesNotTaken: 4, Flags: "----", Operands: []Operand{{Name: "1", Immediate: true}, {Name: "HL"}}},
+	0x8F: {Mnemonic: "RES", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "1", Immediate: true}, {Name: "A", Immediate: true}}},
+	0x90: {Mnemonic: "RES", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "2", Immediate: true}, {Name: "B", Immediate: true}}},
+	0x91: {Mnemonic: "RES", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "2", Immediate: true}, {Name: "C", Immediate: true}}},
+	0x92: {Mnemonic: "RES", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "2", Immediate: true}, {Name: "D", Immediate: true}}},
+	0x93: {Mnemonic: "RES", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "2", Immediate: true}, {Name: "E", Immediate: true}}},
+	0x94: {Mnemonic: "RES", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "2", Immediate: true}, {Name: "H", Immediate: true}}},
+	0x95: {Mnemonic: "RES", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "2", Immediate: true}, {Name: "L", Immediate: true}}},
+	0x96: {Mnemonic: "RES", Bytes: 2, Cycles: 4, CyclesNotTaken: 4, Flags: "----", Operands: []Operand{{Name: "2", Immediate: true}, {Name: "HL"}}},
+	0x97: {Mnemonic: "RES", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "2", Immediate: true}, {Name: "A", Immediate: true}}},
+	0x98: {Mnemonic: "RES", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "3", Immediate: true}, {Name: "B", Immediate: true}}},
+	0x99: {Mnemonic: "RES", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "3", Immediate: true}, {Name: "C", Immediate: true}}},
+	0x9A: {Mnemonic: "RES", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "3", Immediate: true}, {Name: "D", Immediate: true}}},
+	0x9B: {Mnemonic: "RES", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "3", Immediate: true}, {Name: "E", Immediate: true}}},
+	0x9C: {Mnemonic: "RES", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "3", Immediate: true}, {Name: "H", Immediate: true}}},
+	0x9D: {Mnemonic: "RES", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "3", Immediate: true}, {Name: "L", Immediate: true}}},
+	0x9E: {Mnemonic: "RES", Bytes: 2, Cycles: 4, CyclesNotTaken: 4, Flags: "----", Operands: []Operand{{Name: "3", Immediate: true}, {Name: "HL"}}},
+	0x9F: {Mnemonic: "RES", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "3", Immediate: true}, {Name: "A", Immediate: true}}},
+	0xA0: {Mnemonic: "RES", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "4", Immediate: true}, {Name: "B", Immediate: true}}},
+	0xA1: {Mnemonic: "RES", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "4", Immediate: true}, {Name: "C", Immediate: true}}},
+	0xA2: {Mnemonic: "RES", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "4", Immediate: true}, {Name: "D", Immediate: true}}},
+	0xA3: {Mnemonic: "RES", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "4", Immediate: true}, {Name: "E", Immediate: true}}},
+	0xA4: {Mnemonic: "RES", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "4", Immediate: true}, {Name: "H", Immediate: true}}},
+	0xA5: {Mnemonic: "RES", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "4", Immediate: true}, {Name: "L", Immediate: true}}},
+	0xA6: {Mnemonic: "RES", Bytes: 2, Cycles: 4, CyclesNotTaken: 4, Flags: "----", Operands: []Operand{{Name: "4", Immediate: true}, {Name: "HL"}}},
+	0xA7: {Mnemonic: "RES", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "4", Immediate: true}, {Name: "A", Immediate: true}}},
+	0xA8: {Mnemonic: "RES", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "5", Immediate: true}, {Name: "B", Immediate: true}}},
+	0xA9: {Mnemonic: "RES", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "5", Immediate: true}, {Name: "C", Immediate: true}}},
+	0xAA: {Mnemonic: "RES", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "5", Immediate: true}, {Name: "D", Immediate: true}}},
+	0xAB: {Mnemonic: "RES", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "5", Immediate: true}, {Name: "E", Immediate: true}}},
+	0xAC: {Mnemonic: "RES", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "5", Immediate: true}, {Name: "H", Immediate: true}}},
+	0xAD: {Mnemonic: "RES", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "5", Immediate: true}, {Name: "L", Immediate: true}}},
+	0xAE: {Mnemonic: "RES", Bytes: 2, Cycles: 4, CyclesNotTaken: 4, Flags: "----", Operands: []Operand{{Name: "5", Immediate: true}, {Name: "HL"}}},
+	0xAF: {Mnemonic: "RES", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "5", Immediate: true}, {Name: "A", Immediate: true}}},
+	0xB0: {Mnemonic: "RES", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "6", Immediate: true}, {Name: "B", Immediate: true}}},
+	0xB1: {Mnemonic: "RES", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "6", Immediate: true}, {Name: "C", Immediate: true}}},
+	0xB2: {Mnemonic: "RES", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "6", Immediate: true}, {Name: "D", Immediate: true}}},
+	0xB3: {Mnemonic: "RES", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "6", Immediate: true}, {Name: "E", Immediate: true}}},
+	0xB4: {Mnemonic: "RES", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "6", Immediate: true}, {Name: "H", Immediate: true}}},
+	0xB5: {Mnemonic: "RES", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "6", Immediate: true}, {Name: "L", Immediate: true}}},
+	0xB6: {Mnemonic: "RES", Bytes: 2, Cycles: 4, CyclesNotTaken: 4, Flags: "----", Operands: []Operand{{Name: "6", Immediate: true}, {Name: "HL"}}},
+	0xB7: {Mnemonic: "RES", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "6", Immediate: true}, {Name: "A", Immediate: true}}},
+	0xB8: {Mnemonic: "RES", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "7", Immediate: true}, {Name: "B", Immediate: true}}},
+	0xB9: {Mnemonic: "RES", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "7", Immediate: true}, {Name: "C", Immediate: true}}},
+	0xBA: {Mnemonic: "RES", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "7", Immediate: true}, {Name: "D", Immediate: true}}},
+	0xBB: {Mnemonic: "RES", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "7", Immediate: true}, {Name: "E", Immediate: true}}},
+	0xBC: {Mnemonic: "RES", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "7", Immediate: true}, {Name: "H", Immediate: true}}},
+	0xBD: {Mnemonic: "RES", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "7", Immediate: true}, {Name: "L", Immediate: true}}},
+	0xBE: {Mnemonic: "RES", Bytes: 2, Cycles: 4, CyclesNotTaken: 4, Flags: "----", Operands: []Operand{{Name: "7", Immediate: true}, {Name: "HL"}}},
+	0xBF: {Mnemonic: "RES", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "7", Immediate: true}, {Name: "A", Immediate: true}}},
+	0xC0: {Mnemonic: "SET", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "0", Immediate: true}, {Name: "B", Immediate: true}}},
+	0xC1: {Mnemonic: "SET", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "0", Immediate: true}, {Name: "C", Immediate: true}}},
+	0xC2: {Mnemonic: "SET", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "0", Immediate: true}, {Name: "D", Immediate: true}}},
+	0xC3: {Mnemonic: "SET", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "0", Immediate: true}, {Name: "E", Immediate: true}}},
+	0xC4: {Mnemonic: "SET", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "0", Immediate: true}, {Name: "H", Immediate: true}}},
+	0xC5: {Mnemonic: "SET", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "0", Immediate: true}, {Name: "L", Immediate: true}}},
+	0xC6: {Mnemonic: "SET", Bytes: 2, Cycles: 4, CyclesNotTaken: 4, Flags: "----", Operands: []Operand{{Name: "0", Immediate: true}, {Name: "HL"}}},
+	0xC7: {Mnemonic: "SET", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "0", Immediate: true}, {Name: "A", Immediate: true}}},
+	0xC8: {Mnemonic: "SET", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "1", Immediate: true}, {Name: "B", Immediate: true}}},
+	0xC9: {Mnemonic: "SET", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "1", Immediate: true}, {Name: "C", Immediate: true}}},
+	0xCA: {Mnemonic: "SET", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "1", Immediate: true}, {Name: "D", Immediate: true}}},
+	0xCB: {Mnemonic: "SET", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "1", Immediate: true}, {Name: "E", Immediate: true}}},
+	0xCC: {Mnemonic: "SET", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "1", Immediate: true}, {Name: "H", Immediate: true}}},
+	0xCD: {Mnemonic: "SET", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "1", Immediate: true}, {Name: "L", Immediate: true}}},
+	0xCE: {Mnemonic: "SET", Bytes: 2, Cycles: 4, CyclesNotTaken: 4, Flags: "----", Operands: []Operand{{Name: "1", Immediate: true}, {Name: "HL"}}},
+	0xCF: {Mnemonic: "SET", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "1", Immediate: true}, {Name: "A", Immediate: true}}},
+	0xD0: {Mnemonic: "SET", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "2", Immediate: true}, {Name: "B", Immediate: true}}},
+	0xD1: {Mnemonic: "SET", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "2", Immediate: true}, {Name: "C", Immediate: true}}},
+	0xD2: {Mnemonic: "SET", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "2", Immediate: true}, {Name: "D", Immediate: true}}},
+	0xD3: {Mnemonic: "SET", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "2", Immediate: true}, {Name: "E", Immediate: true}}},
+	0xD4: {Mnemonic: "SET", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "2", Immediate: true}, {Name: "H", Immediate: true}}},
+	0xD5: {Mnemonic: "SET", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "2", Immediate: true}, {Name: "L", Immediate: true}}},
+	0xD6: {Mnemonic: "SET", Bytes: 2, Cycles: 4, CyclesNotTaken: 4, Flags: "----", Operands: []Operand{{Name: "2", Immediate: true}, {Name: "HL"}}},
+	0xD7: {Mnemonic: "SET", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "2", Immediate: true}, {Name: "A", Immediate: true}}},
+	0xD8: {Mnemonic: "SET", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "3", Immediate: true}, {Name: "B", Immediate: true}}},
+	0xD9: {Mnemonic: "SET", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "3", Immediate: true}, {Name: "C", Immediate: true}}},
+	0xDA: {Mnemonic: "SET", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "3", Immediate: true}, {Name: "D", Immediate: true}}},
+	0xDB: {Mnemonic: "SET", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "3", Immediate: true}, {Name: "E", Immediate: true}}},
+	0xDC: {Mnemonic: "SET", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "3", Immediate: true}, {Name: "H", Immediate: true}}},
+	0xDD: {Mnemonic: "SET", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "3", Immediate: true}, {Name: "L", Immediate: true}}},
+	0xDE: {Mnemonic: "SET", Bytes: 2, Cycles: 4, CyclesNotTaken: 4, Flags: "----", Operands: []Operand{{Name: "3", Immediate: true}, {Name: "HL"}}},
+	0xDF: {Mnemonic: "SET", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "3", Immediate: true}, {Name: "A", Immediate: true}}},
+	0xE0: {Mnemonic: "SET", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "4", Immediate: true}, {Name: "B", Immediate: true}}},
+	0xE1: {Mnemonic: "SET", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "4", Immediate: true}, {Name: "C", Immediate: true}}},
+	0xE2: {Mnemonic: "SET", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "4", Immediate: true}, {Name: "D", Immediate: true}}},
+	0xE3: {Mnemonic: "SET", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "4", Immediate: true}, {Name: "E", Immediate: true}}},
+	0xE4: {Mnemonic: "SET", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "4", Immediate: true}, {Name: "H", Immediate: true}}},
+	0xE5: {Mnemonic: "SET", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "4", Immediate: true}, {Name: "L", Immediate: true}}},
+	0xE6: {Mnemonic: "SET", Bytes: 2, Cycles: 4, CyclesNotTaken: 4, Flags: "----", Operands: []Operand{{Name: "4", Immediate: true}, {Name: "HL"}}},
+	0xE7: {Mnemonic: "SET", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "4", Immediate: true}, {Name: "A", Immediate: true}}},
+	0xE8: {Mnemonic: "SET", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "5", Immediate: true}, {Name: "B", Immediate: true}}},
+	0xE9: {Mnemonic: "SET", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "5", Immediate: true}, {Name: "C", Immediate: true}}},
+	0xEA: {Mnemonic: "SET", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "5", Immediate: true}, {Name: "D", Immediate: true}}},
+	0xEB: {Mnemonic: "SET", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "5", Immediate: true}, {Name: "E", Immediate: true}}},
+	0xEC: {Mnemonic: "SET", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "5", Immediate: true}, {Name: "H", Immediate: true}}},
+	0xED: {Mnemonic: "SET", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "5", Immediate: true}, {Name: "L", Immediate: true}}},
+	0xEE: {Mnemonic: "SET", Bytes: 2, Cycles: 4, CyclesNotTaken: 4, Flags: "----", Operands: []Operand{{Name: "5", Immediate: true}, {Name: "HL"}}},
+	0xEF: {Mnemonic: "SET", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "5", Immediate: true}, {Name: "A", Immediate: true}}},
+	0xF0: {Mnemonic: "SET", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "6", Immediate: true}, {Name: "B", Immediate: true}}},
+	0xF1: {Mnemonic: "SET", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "6", Immediate: true}, {Name: "C", Immediate: true}}},
+	0xF2: {Mnemonic: "SET", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "6", Immediate: true}, {Name: "D", Immediate: true}}},
+	0xF3: {Mnemonic: "SET", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "6", Immediate: true}, {Name: "E", Immediate: true}}},
+	0xF4: {Mnemonic: "SET", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "6", Immediate: true}, {Name: "H", Immediate: true}}},
+	0xF5: {Mnemonic: "SET", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "6", Immediate: true}, {Name: "L", Immediate: true}}},
+	0xF6: {Mnemonic: "SET", Bytes: 2, Cycles: 4, CyclesNotTaken: 4, Flags: "----", Operands: []Operand{{Name: "6", Immediate: true}, {Name: "HL"}}},
+	0xF7: {Mnemonic: "SET", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "6", Immediate: true}, {Name: "A", Immediate: true}}},
+	0xF8: {Mnemonic: "SET", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "7", Immediate: true}, {Name: "B", Immediate: true}}},
+	0xF9: {Mnemonic: "SET", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "7", Immediate: true}, {Name: "C", Immediate: true}}},
+	0xFA: {Mnemonic: "SET", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "7", Immediate: true}, {Name: "D", Immediate: true}}},
+	0xFB: {Mnemonic: "SET", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "7", Immediate: true}, {Name: "E", Immediate: true}}},
+	0xFC: {Mnemonic: "SET", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "7", Immediate: true}, {Name: "H", Immediate: true}}},
+	0xFD: {Mnemonic: "SET", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "7", Immediate: true}, {Name: "L", Immediate: true}}},
+	0xFE: {Mnemonic: "SET", Bytes: 2, Cycles: 4, CyclesNotTaken: 4, Flags: "----", Operands: []Operand{{Name: "7", Immediate: true}, {Name: "HL"}}},
+	0xFF: {Mnemonic: "SET", Bytes: 2, Cycles: 2, CyclesNotTaken: 2, Flags: "----", Operands: []Operand{{Name: "7", Immediate: true}, {Name: "A", Immediate: true}}},
+}
+
+/* [DEBUG] Opcode to instruction map */
+var InstrDebugLookup = [0x100]string{
+	0x00: "NOP ",
+	0x01: "LD BC,n16",
+	0x02: "LD [BC],A",
+	0x03: "INC BC",
+	0x04: "INC B",
+	0x05: "DEC B",
+	0x06: "LD B,n8",
+	0x07: "RLCA ",
+	0x08: "LD [a16],SP",
+	0x09: "ADD HL,BC",
+	0x0A: "LD A,[BC]",
+	0x0B: "DEC BC",
+	0x0C: "INC C",
+	0x0D: "DEC C",
+	0x0E: "LD C,n8",
+	0x0F: "RRCA ",
+	0x10: "STOP n8",
+	0x11: "LD DE,n16",
+	0x12: "LD [DE],A",
+	0x13: "INC DE",
+	0x14: "INC D",
+	0x15: "DEC D",
+	0x16: "LD D,n8",
+	0x17: "RLA ",
+	0x18: "JR e8",
+	0x19: "ADD HL,DE",
+	0x1A: "LD A,[DE]",
+	0x1B: "DEC DE",
+	0x1C: "INC E",
+	0x1D: "DEC E",
+	0x1E: "LD E,n8",
+	0x1F: "RRA ",
+	0x20: "JR NZ,e8",
+	0x21: "LD HL,n16",
+	0x22: "LDI [HL],A",
+	0x23: "INC HL",
+	0x24: "INC H",
+	0x25: "DEC H",
+	0x26: "LD H,n8",
+	0x27: "DAA ",
+	0x28: "JR Z,e8",
+	0x29: "ADD HL,HL",
+	0x2A: "LDI A,[HL]",
+	0x2B: "DEC HL",
+	0x2C: "INC L",
+	0x2D: "DEC L",
+	0x2E: "LD L,n8",
+	0x2F: "CPL ",
+	0x30: "JR NC,e8",
+	0x31: "LD SP,n16",
+	0x32: "LDD [HL],A",
+	0x33: "INC SP",
+	0x34: "INC [HL]",
+	0x35: "DEC [HL]",
+	0x36: "LD [HL],n8",
+	0x37: "SCF ",
+	0x38: "JR C,e8",
+	0x39: "ADD HL,SP",
+	0x3A: "LDD A,[HL]",
+	0x3B: "DEC SP",
+	0x3C: "INC A",
+	0x3D: "DEC A",
+	0x3E: "LD A,n8",
+	0x3F: "CCF ",
+	0x40: "LD B,B",
+	0x41: "LD B,C",
+	0x42: "LD B,D",
+	0x43: "LD B,E",
+	0x44: "LD B,H",
+	0x45: "LD B,L",
+	0x46: "LD B,[HL]",
+	0x47: "LD B,A",
+	0x48: "LD C,B",
+	0x49: "LD C,C",
+	0x4A: "LD C,D",
+	0x4B: "LD C,E",
+	0x4C: "LD C,H",
+	0x4D: "LD C,L",
+	0x4E: "LD C,[HL]",
+	0x4F: "LD C,A",
+	0x50: "LD D,B",
+	0x51: "LD D,C",
+	0x52: "LD D,D",
+	0x53: "LD D,E",
+	0x54: "LD D,H",
+	0x55: "LD D,L",
+	0x56: "LD D,[HL]",
+	0x57: "LD D,A",
+	0x58: "LD E,B",
+	0x59: "LD E,C",
+	0x5A: "LD E,D",
+	0x5B: "LD E,E",
+	0x5C: "LD E,H",
+	0x5D: "LD E,L",
+	0x5E: "LD E,[HL]",
+	0x5F: "LD E,A",
+	0x60: "LD H,B",
+	0x61: "LD H,C",
+	0x62: "LD H,D",
+	0x63: "LD H,E",
+	0x64: "LD H,H",
+	0x65: "LD H,L",
+	0x66: "LD H,[HL]",
+	0x67: "LD H,A",
+	0x68: "LD L,B",
+	0x69: "LD L,C",
+	0x6A: "LD L,D",
+	0x6B: "LD L,E",
+	0x6C: "LD L,H",
+	0x6D: "LD L,L",
+	0x6E: "LD L,[HL]",
+	0x6F: "LD L,A",
+	0x70: "LD [HL],B",
+	0x71: "LD [HL],C",
+	0x72: "LD [HL],D",
+	0x73: "LD [HL],E",
+	0x74: "LD [HL],H",
+	0x75: "LD [HL],L",
+	0x76: "HALT ",
+	0x77: "LD [HL],A",
+	0x78: "LD A,B",
+	0x79: "LD A,C",
+	0x7A: "LD A,D",
+	0x7B: "LD A,E",
+	0x7C: "LD A,H",
+	0x7D: "LD A,L",
+	0x7E: "LD A,[HL]",
+	0x7F: "LD A,A",
+	0x80: "ADD A,B",
+	0x81: "ADD A,C",
+	0x82: "ADD A,D",
+	0x83: "ADD A,E",
+	0x84: "ADD A,H",
+	0x85: "ADD A,L",
+	0x86: "ADD A,[HL]",
+	0x87: "ADD A,A",
+	0x88: "ADC A,B",
+	0x89: "ADC A,C",
+	0x8A: "ADC A,D",
+	0x8B: "ADC A,E",
+	0x8C: "ADC A,H",
+	0x8D: "ADC A,L",
+	0x8E: "ADC A,[HL]",
+	0x8F: "ADC A,A",
+	0x90: "SUB A,B",
+	0x91: "SUB A,C",
+	0x92: "SUB A,D",
+	0x93: "SUB A,E",
+	0x94: "SUB A,H",
+	0x95: "SUB A,L",
+	0x96: "SUB A,[HL]",
+	0x97: "SUB A,A",
+	0x98: "SBC A,B",
+	0x99: "SBC A,C",
+	0x9A: "SBC A,D",
+	0x9B: "SBC A,E",
+	0x9C: "SBC A,H",
+	0x9D: "SBC A,L",
+	0x9E: "SBC A,[HL]",
+	0x9F: "SBC A,A",
+	0xA0: "AND A,B",
+	0xA1: "AND A,C",
+	0xA2: "AND A,D",
+	0xA3: "AND A,E",
+	0xA4: "AND A,H",
+	0xA5: "AND A,L",
+	0xA6: "AND A,[HL]",
+	0xA7: "AND A,A",
+	0xA8: "XOR A,B",
+	0xA9: "XOR A,C",
+	0xAA: "XOR A,D",
+	0xAB: "XOR A,E",
+	0xAC: "XOR A,H",
+	0xAD: "XOR A,L",
+	0xAE: "XOR A,[HL]",
+	0xAF: "XOR A,A",
+	0xB0: "OR A,B",
+	0xB1: "OR A,C",
+	0xB2: "OR A,D",
+	0xB3: "OR A,E",
+	0xB4: "OR A,H",
+	0xB5: "OR A,L",
+	0xB6: "OR A,[HL]",
+	0xB7: "OR A,A",
+	0xB8: "CP A,B",
+	0xB9: "CP A,C",
+	0xBA: "CP A,D",
+	0xBB: "CP A,E",
+	0xBC: "CP A,H",
+	0xBD: "CP A,L",
+	0xBE: "CP A,[HL]",
+	0xBF: "CP A,A",
+	0xC0: "RET NZ",
+	0xC1: "POP BC",
+	0xC2: "JP NZ,a16",
+	0xC3: "JP a16",
+	0xC4: "CALL NZ,a16",
+	0xC5: "PUSH BC",
+	0xC6: "ADD A,n8",
+	0xC7: "RST $00",
+	0xC8: "RET Z",
+	0xC9: "RET ",
+	0xCA: "JP Z,a16",
+	0xCB: "PREFIX ",
+	0xCC: "CALL Z,a16",
+	0xCD: "CALL a16",
+	0xCE: "ADC A,n8",
+	0xCF: "RST $08",
+	0xD0: "RET NC",
+	0xD1: "POP DE",
+	0xD2: "JP NC,a16",
+	0xD3: "ILLEGAL_D3 ",
+	0xD4: "CALL NC,a16",
+	0xD5: "PUSH DE",
+	0xD6: "SUB A,n8",
+	0xD7: "RST $10",
+	0xD8: "RET C",
+	0xD9: "RETI ",
+	0xDA: "JP C,a16",
+	0xDB: "ILLEGAL_DB ",
+	0xDC: "CALL C,a16",
+	0xDD: "ILLEGAL_DD ",
+	0xDE: "SBC A,n8",
+	0xDF: "RST $18",
+	0xE0: "LDH [a8],A",
+	0xE1: "POP HL",
+	0xE2: "LD [C],A",
+	0xE3: "ILLEGAL_E3 ",
+	0xE4: "ILLEGAL_E4 ",
+	0xE5: "PUSH HL",
+	0xE6: "AND A,n8",
+	0xE7: "RST $20",
+	0xE8: "ADD SP,e8",
+	0xE9: "JP HL",
+	0xEA: "LD [a16],A",
+	0xEB: "ILLEGAL_EB ",
+	0xEC: "ILLEGAL_EC ",
+	0xED: "ILLEGAL_ED ",
+	0xEE: "XOR A,n8",
+	0xEF: "RST $28",
+	0xF0: "LDH A,[a8]",
+	0xF1: "POP AF",
+	0xF2: "LD A,[C]",
+	0xF3: "DI ",
+	0xF4: "ILLEGAL_F4 ",
+	0xF5: "PUSH AF",
+	0xF6: "OR A,n8",
+	0xF7: "RST $30",
+	0xF8: "LDI HL,SP,e8",
+	0xF9: "LD SP,HL",
+	0xFA: "LD A,[a16]",
+	0xFB: "EI ",
+	0xFC: "ILLEGAL_FC ",
+	0xFD: "ILLEGAL_FD ",
+	0xFE: "CP A,n8",
+	0xFF: "RST $38",
+}
+
+/* [DEBUG] Opcode to instruction map */
+var PrefixInstrDebugLookup = [0x100]string{
+	0x00: "RLC B",
+	0x01: "RLC C",
+	0x02: "RLC D",
+	0x03: "RLC E",
+	0x04: "RLC H",
+	0x05: "RLC L",
+	0x06: "RLC [HL]",
+	0x07: "RLC A",
+	0x08: "RRC B",
+	0x09: "RRC C",
+	0x0A: "RRC D",
+	0x0B: "RRC E",
+	0x0C: "RRC H",
+	0x0D: "RRC L",
+	0x0E: "RRC [HL]",
+	0x0F: "RRC A",
+	0x10: "RL B",
+	0x11: "RL C",
+	0x12: "RL D",
+	0x13: "RL E",
+	0x14: "RL H",
+	0x15: "RL L",
+	0x16: "RL [HL]",
+	0x17: "RL A",
+	0x18: "RR B",
+	0x19: "RR C",
+	0x1A: "RR D",
+	0x1B: "RR E",
+	0x1C: "RR H",
+	0x1D: "RR L",
+	0x1E: "RR [HL]",
+	0x1F: "RR A",
+	0x20: "SLA B",
+	0x21: "SLA C",
+	0x22: "SLA D",
+	0x23: "SLA E",
+	0x24: "SLA H",
+	0x25: "SLA L",
+	0x26: "SLA [HL]",
+	0x27: "SLA A",
+	0x28: "SRA B",
+	0x29: "SRA C",
+	0x2A: "SRA D",
+	0x2B: "SRA E",
+	0x2C: "SRA H",
+	0x2D: "SRA L",
+	0x2E: "SRA [HL]",
+	0x2F: "SRA A",
+	0x30: "SWAP B",
+	0x31: "SWAP C",
+	0x32: "SWAP D",
+	0x33: "SWAP E",
+	0x34: "SWAP H",
+	0x35: "SWAP L",
+	0x36: "SWAP [HL]",
+	0x37: "SWAP A",
+	0x38: "SRL B",
+	0x39: "SRL C",
+	0x3A: "SRL D",
+	0x3B: "SRL E",
+	0x3C: "SRL H",
+	0x3D: "SRL L",
+	0x3E: "SRL [HL]",
+	0x3F: "SRL A",
+	0x40: "BIT 0,B",
+	0x41: "BIT 0,C",
+	0x42: "BIT 0,D",
+	0x43: "BIT 0,E",
+	0x44: "BIT 0,H",
+	0x45: "BIT 0,L",
+	0x46: "BIT 0,[HL]",
+	0x47: "BIT 0,A",
+	0x48: "BIT 1,B",
+	0x49: "BIT 1,C",
+	0x4A: "BIT 1,D",
+	0x4B: "BIT 1,E",
+	0x4C: "BIT 1,H",
+	0x4D: "BIT 1,L",
+	0x4E: "BIT 1,[HL]",
+	0x4F: "BIT 1,A",
+	0x50: "BIT 2,B",
+	0x51: "BIT 2,C",
+	0x52: "BIT 2,D",
+	0x53: "BIT 2,E",
+	0x54: "BIT 2,H",
+	0x55: "BIT 2,L",
+	0x56: "BIT 2,[HL]",
+	0x57: "BIT 2,A",
+	0x58: "BIT 3,B",
+	0x59: "BIT 3,C",
+	0x5A: "BIT 3,D",
+	0x5B: "BIT 3,E",
+	0x5C: "BIT 3,H",
+	0x5D: "BIT 3,L",
+	0x5E: "BIT 3,[HL]",
+	0x5F: "BIT 3,A",
+	0x60: "BIT 4,B",
+	0x61: "BIT 4,C",
+	0x62: "BIT 4,D",
+	0x63: "BIT 4,E",
+	0x64: "BIT 4,H",
+	0x65: "BIT 4,L",
+	0x66: "BIT 4,[HL]",
+	0x67: "BIT 4,A",
+	0x68: "BIT 5,B",
+	0x69: "BIT 5,C",
+	0x6A: "BIT 5,D",
+	0x6B: "BIT 5,E",
+	0x6C: "BIT 5,H",
+	0x6D: "BIT 5,L",
+	0x6E: "BIT 5,[HL]",
+	0x6F: "BIT 5,A",
+	0x70: "BIT 6,B",
+	0x71: "BIT 6,C",
+	0x72: "BIT 6,D",
+	0x73: "BIT 6,E",
+	0x74: "BIT 6,H",
+	0x75: "BIT 6,L",
+	0x76: "BIT 6,[HL]",
+	0x77: "BIT 6,A",
+	0x78: "BIT 7,B",
+	0x79: "BIT 7,C",
+	0x7A: "BIT 7,D",
+	0x7B: "BIT 7,E",
+	0x7C: "BIT 7,H",
+	0x7D: "BIT 7,L",
+	0x7E: "BIT 7,[HL]",
+	0x7F: "BIT 7,A",
+	0x80: "RES 0,B",
+	0x81: "RES 0,C",
+	0x82: "RES 0,D",
+	0x83: "RES 0,E",
+	0x84: "RES 0,H",
+	0x85: "RES 0,L",
+	0x86: "RES 0,[HL]",
+	0x87: "RES 0,A",
+	0x88: "RES 1,B",
+	0x89: "RES 1,C",
+	0x8A: "RES 1,D",
+	0x8B: "RES 1,E",
+	0x8C: "RES 1,H",
+	0x8D: "RES 1,L",
+	0x8E: "RES 1,[HL]",
+	0x8F: "RES 1,A",
+	0x90: "RES 2,B",
+	0x91: "RES 2,C",
+	0x92: "RES 2,D",
+	0x93: "RES 2,E",
+	0x94: "RES 2,H",
+	0x95: "RES 2,L",
+	0x96: "RES 2,[HL]",
+	0x97: "RES 2,A",
+	0x98: "RES 3,B",
+	0x99: "RES 3,C",
+	0x9A: "RES 3,D",
+	0x9B: "RES 3,E",
+	0x9C: "RES 3,H",
+	0x9D: "RES 3,L",
+	0x9E: "RES 3,[HL]",
+	0x9F: "RES 3,A",
+	0xA0: "RES 4,B",
+	0xA1: "RES 4,C",
+	0xA2: "RES 4,D",
+	0xA3: "RES 4,E",
+	0xA4: "RES 4,H",
+	0xA5: "RES 4,L",
+	0xA6: "RES 4,[HL]",
+	0xA7: "RES 4,A",
+	0xA8: "RES 5,B",
+	0xA9: "RES 5,C",
+	0xAA: "RES 5,D",
+	0xAB: "RES 5,E",
+	0xAC: "RES 5,H",
+	0xAD: "RES 5,L",
+	0xAE: "RES 5,[HL]",
+	0xAF: "RES 5,A",
+	0xB0: "RES 6,B",
+	0xB1: "RES 6,C",
+	0xB2: "RES 6,D",
+	0xB3: "RES 6,E",
+	0xB4: "RES 6,H",
+	0xB5: "RES 6,L",
+	0xB6: "RES 6,[HL]",
+	0xB7: "RES 6,A",
+	0xB8: "RES 7,B",
+	0xB9: "RES 7,C",
+	0xBA: "RES 7,D",
+	0xBB: "RES 7,E",
+	0xBC: "RES 7,H",
+	0xBD: "RES 7,L",
+	0xBE: "RES 7,[HL]",
+	0xBF: "RES 7,A",
+	0xC0: "SET 0,B",
+	0xC1: "SET 0,C",
+	0xC2: "SET 0,D",
+	0xC3: "SET 0,E",
+	0xC4: "SET 0,H",
+	0xC5: "SET 0,L",
+	0xC6: "SET 0,[HL]",
+	0xC7: "SET 0,A",
+	0xC8: "SET 1,B",
+	0xC9: "SET 1,C",
+	0xCA: "SET 1,D",
+	0xCB: "SET 1,E",
+	0xCC: "SET 1,H",
+	0xCD: "SET 1,L",
+	0xCE: "SET 1,[HL]",
+	0xCF: "SET 1,A",
+	0xD0: "SET 2,B",
+	0xD1: "SET 2,C",
+	0xD2: "SET 2,D",
+	0xD3: "SET 2,E",
+	0xD4: "SET 2,H",
+	0xD5: "SET 2,L",
+	0xD6: "SET 2,[HL]",
+	0xD7: "SET 2,A",
+	0xD8: "SET 3,B",
+	0xD9: "SET 3,C",
+	0xDA: "SET 3,D",
+	0xDB: "SET 3,E",
+	0xDC: "SET 3,H",
+	0xDD: "SET 3,L",
+	0xDE: "SET 3,[HL]",
+	0xDF: "SET 3,A",
+	0xE0: "SET 4,B",
+	0xE1: "SET 4,C",
+	0xE2: "SET 4,D",
+	0xE3: "SET 4,E",
+	0xE4: "SET 4,H",
+	0xE5: "SET 4,L",
+	0xE6: "SET 4,[HL]",
+	0xE7: "SET 4,A",
+	0xE8: "SET 5,B",
+	0xE9: "SET 5,C",
+	0xEA: "SET 5,D",
+	0xEB: "SET 5,E",
+	0xEC: "SET 5,H",
+	0xED: "SET 5,L",
+	0xEE: "SET 5,[HL]",
+	0xEF: "SET 5,A",
+	0xF0: "SET 6,B",
+	0xF1: "SET 6,C",
+	0xF2: "SET 6,D",
+	0xF3: "SET 6,E",
+	0xF4: "SET 6,H",
+	0xF5: "SET 6,L",
+	0xF6: "SET 6,[HL]",
+	0xF7: "SET 6,A",
+	0xF8: "SET 7,B",
+	0xF9: "SET 7,C",
+	0xFA: "SET 7,D",
+	0xFB: "SET 7,E",
+	0xFC: "SET 7,H",
+	0xFD: "SET 7,L",
+	0xFE: "SET 7,[HL]",
+	0xFF: "SET 7,A",
+}
+
+// OpcodeCycles is the number of M-cycles each unprefixed opcode takes, when
+// the condition fails for conditional instructions.
+var OpcodeCycles = []int{
+	1, 3, 2, 2, 1, 1, 2, 1, 5, 2, 2, 2, 1, 1, 2, 1, // 0
+	1, 3, 2, 2, 1, 1, 2, 1, 3, 2, 2, 2, 1, 1, 2, 1, // 1
+	2, 3, 2, 2, 1, 1, 2, 1, 2, 2, 2, 2, 1, 1, 2, 1, // 2
+	2, 3, 2, 2, 3, 3, 3, 1, 2, 2, 2, 2, 1, 1, 2, 1, // 3
+	1, 1, 1, 1, 1, 1, 2, 1, 1, 1, 1, 1, 1, 1, 2, 1, // 4
+	1, 1, 1, 1, 1, 1, 2, 1, 1, 1, 1, 1, 1, 1, 2, 1, // 5
+	1, 1, 1, 1, 1, 1, 2, 1, 1, 1, 1, 1, 1, 1, 2, 1, // 6
+	2, 2, 2, 2, 2, 2, 1, 2, 1, 1, 1, 1, 1, 1, 2, 1, // 7
+	1, 1, 1, 1, 1, 1, 2, 1, 1, 1, 1, 1, 1, 1, 2, 1, // 8
+	1, 1, 1, 1, 1, 1, 2, 1, 1, 1, 1, 1, 1, 1, 2, 1, // 9
+	1, 1, 1, 1, 1, 1, 2, 1, 1, 1, 1, 1, 1, 1, 2, 1, // a
+	1, 1, 1, 1, 1, 1, 2, 1, 1, 1, 1, 1, 1, 1, 2, 1, // b
+	2, 3, 3, 4, 3, 4, 2, 4, 2, 4, 3, 1, 3, 6, 2, 4, // c
+	2, 3, 3, 1, 3, 4, 2, 4, 2, 4, 3, 1, 3, 1, 2, 4, // d
+	3, 3, 2, 1, 1, 4, 2, 4, 4, 1, 4, 1, 1, 1, 2, 4, // e
+	3, 3, 2, 1, 1, 4, 2, 4, 3, 2, 4, 1, 1, 1, 2, 4, // f
+}
+
+// CBOpcodeCycles is the number of M-cycles each prefixed opcode takes,
+// including the prefix.
+var CBOpcodeCycles = []int{
+	2, 2, 2, 2, 2, 2, 4, 2, 2, 2, 2, 2, 2, 2, 4, 2, // 0
+	2, 2, 2, 2, 2, 2, 4, 2, 2, 2, 2, 2, 2, 2, 4, 2, // 1
+	2, 2, 2, 2, 2, 2, 4, 2, 2, 2, 2, 2, 2, 2, 4, 2, // 2
+	2, 2, 2, 2, 2, 2, 4, 2, 2, 2, 2, 2, 2, 2, 4, 2, // 3
+	2, 2, 2, 2, 2, 2, 3, 2, 2, 2, 2, 2, 2, 2, 3, 2, // 4
+	2, 2, 2, 2, 2, 2, 3, 2, 2, 2, 2, 2, 2, 2, 3, 2, // 5
+	2, 2, 2, 2, 2, 2, 3, 2, 2, 2, 2, 2, 2, 2, 3, 2, // 6
+	2, 2, 2, 2, 2, 2, 3, 2, 2, 2, 2, 2, 2, 2, 3, 2, // 7
+	2, 2, 2, 2, 2, 2, 4, 2, 2, 2, 2, 2, 2, 2, 4, 2, // 8
+	2, 2, 2, 2, 2, 2, 4, 2, 2, 2, 2, 2, 2, 2, 4, 2, // 9
+	2, 2, 2, 2, 2, 2, 4, 2, 2, 2, 2, 2, 2, 2, 4, 2, // a
+	2, 2, 2, 2, 2, 2, 4, 2, 2, 2, 2, 2, 2, 2, 4, 2, // b
+	2, 2, 2, 2, 2, 2, 4, 2, 2, 2, 2, 2, 2, 2, 4, 2, // c
+	2, 2, 2, 2, 2, 2, 4, 2, 2, 2, 2, 2, 2, 2, 4, 2, // d
+	2, 2, 2, 2, 2, 2, 4, 2, 2, 2, 2, 2, 2, 2, 4, 2, // e
+	2, 2, 2, 2, 2, 2, 4, 2, 2, 2, 2, 2, 2, 2, 4, 2, // f
+}
+
+// InstrLen is the length in bytes of each unprefixed instruction, operands
+// included.
+var InstrLen = [0x100]int{
+	1, 3, 1, 1, 1, 1, 2, 1, 3, 1, 1, 1, 1, 1, 2, 1, // 0
+	2, 3, 1, 1, 1, 1, 2, 1, 2, 1, 1, 1, 1, 1, 2, 1, // 1
+	2, 3, 1, 1, 1, 1, 2, 1, 2, 1, 1, 1, 1, 1, 2, 1, // 2
+	2, 3, 1, 1, 1, 1, 2, 1, 2, 1, 1, 1, 1, 1, 2, 1, // 3
+	1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, // 4
+	1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, // 5
+	1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, // 6
+	1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, // 7
+	1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, // 8
+	1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, // 9
+	1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, // a
+	1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, // b
+	1, 1, 3, 3, 3, 1, 2, 1, 1, 1, 3, 1, 3, 3, 2, 1, // c
+	1, 1, 3, 1, 3, 1, 2, 1, 1, 1, 3, 1, 3, 1, 2, 1, // d
+	2, 1, 1, 1, 1, 1, 2, 1, 2, 1, 3, 1, 1, 1, 2, 1, // e
+	2, 1, 1, 1, 1, 1, 2, 1, 2, 1, 3, 1, 1, 1, 2, 1, // f
+}
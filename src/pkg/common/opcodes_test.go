@@ -0,0 +1,159 @@
+package common
+
+import "testing"
+
+// Reference tables, written out independently of Opcodes.json. STOP, HALT,
+// the 0xCB prefix and the illegal opcodes take a single M-cycle.
+var wantInstrLen = [0x100]int{
+	1, 3, 1, 1, 1, 1, 2, 1, 3, 1, 1, 1, 1, 1, 2, 1,
+	2, 3, 1, 1, 1, 1, 2, 1, 2, 1, 1, 1, 1, 1, 2, 1,
+	2, 3, 1, 1, 1, 1, 2, 1, 2, 1, 1, 1, 1, 1, 2, 1,
+	2, 3, 1, 1, 1, 1, 2, 1, 2, 1, 1, 1, 1, 1, 2, 1,
+	1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
+	1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
+	1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
+	1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
+	1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
+	1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
+	1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
+	1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
+	1, 1, 3, 3, 3, 1, 2, 1, 1, 1, 3, 1, 3, 3, 2, 1,
+	1, 1, 3, 1, 3, 1, 2, 1, 1, 1, 3, 1, 3, 1, 2, 1,
+	2, 1, 1, 1, 1, 1, 2, 1, 2, 1, 3, 1, 1, 1, 2, 1,
+	2, 1, 1, 1, 1, 1, 2, 1, 2, 1, 3, 1, 1, 1, 2, 1,
+}
+
+// M-cycles, when the condition fails for conditional instructions.
+var wantOpcodeCycles = [0x100]int{
+	1, 3, 2, 2, 1, 1, 2, 1, 5, 2, 2, 2, 1, 1, 2, 1,
+	1, 3, 2, 2, 1, 1, 2, 1, 3, 2, 2, 2, 1, 1, 2, 1,
+	2, 3, 2, 2, 1, 1, 2, 1, 2, 2, 2, 2, 1, 1, 2, 1,
+	2, 3, 2, 2, 3, 3, 3, 1, 2, 2, 2, 2, 1, 1, 2, 1,
+	1, 1, 1, 1, 1, 1, 2, 1, 1, 1, 1, 1, 1, 1, 2, 1,
+	1, 1, 1, 1, 1, 1, 2, 1, 1, 1, 1, 1, 1, 1, 2, 1,
+	1, 1, 1, 1, 1, 1, 2, 1, 1, 1, 1, 1, 1, 1, 2, 1,
+	2, 2, 2, 2, 2, 2, 1, 2, 1, 1, 1, 1, 1, 1, 2, 1,
+	1, 1, 1, 1, 1, 1, 2, 1, 1, 1, 1, 1, 1, 1, 2, 1,
+	1, 1, 1, 1, 1, 1, 2, 1, 1, 1, 1, 1, 1, 1, 2, 1,
+	1, 1, 1, 1, 1, 1, 2, 1, 1, 1, 1, 1, 1, 1, 2, 1,
+	1, 1, 1, 1, 1, 1, 2, 1, 1, 1, 1, 1, 1, 1, 2, 1,
+	2, 3, 3, 4, 3, 4, 2, 4, 2, 4, 3, 1, 3, 6, 2, 4,
+	2, 3, 3, 1, 3, 4, 2, 4, 2, 4, 3, 1, 3, 1, 2, 4,
+	3, 3, 2, 1, 1, 4, 2, 4, 4, 1, 4, 1, 1, 1, 2, 4,
+	3, 3, 2, 1, 1, 4, 2, 4, 3, 2, 4, 1, 1, 1, 2, 4,
+}
+
+// M-cycles of conditional instructions when the condition holds.
+var wantTaken = map[int]int{
+	0x20: 3, 0x28: 3, 0x30: 3, 0x38: 3, // JR cc,e8
+	0xC2: 4, 0xCA: 4, 0xD2: 4, 0xDA: 4, // JP cc,a16
+	0xC4: 6, 0xCC: 6, 0xD4: 6, 0xDC: 6, // CALL cc,a16
+	0xC0: 5, 0xC8: 5, 0xD0: 5, 0xD8: 5, // RET cc
+}
+
+func TestInstrLen(t *testing.T) {
+	for op := range InstrLen {
+		if InstrLen[op] != wantInstrLen[op] {
+			t.Errorf("InstrLen[%#02x] (%s) = %d, want %d", op, InstrDebugLookup[op], InstrLen[op], wantInstrLen[op])
+		}
+		if Opcodes[op].Bytes != InstrLen[op] {
+			t.Errorf("Opcodes[%#02x].Bytes = %d, InstrLen has %d", op, Opcodes[op].Bytes, InstrLen[op])
+		}
+	}
+	for op := range CBOpcodes {
+		if CBOpcodes[op].Bytes != 2 {
+			t.Errorf("CBOpcodes[%#02x].Bytes = %d, want 2", op, CBOpcodes[op].Bytes)
+		}
+	}
+}
+
+func TestOpcodeCycles(t *testing.T) {
+	if len(OpcodeCycles) != 0x100 {
+		t.Fatalf("%d entries in OpcodeCycles, want 256", len(OpcodeCycles))
+	}
+	for op := range wantOpcodeCycles {
+		if OpcodeCycles[op] != wantOpcodeCycles[op] {
+			t.Errorf("OpcodeCycles[%#02x] (%s) = %d, want %d", op, InstrDebugLookup[op], OpcodeCycles[op], wantOpcodeCycles[op])
+		}
+		taken, ok := wantTaken[op]
+		if !ok {
+			taken = wantOpcodeCycles[op]
+		}
+		if got := Opcodes[op]; got.Cycles != taken || got.CyclesNotTaken != wantOpcodeCycles[op] {
+			t.Errorf("Opcodes[%#02x] (%s) takes %d/%d M-cycles, want %d/%d", op, InstrDebugLookup[op], got.Cycles, got.CyclesNotTaken, taken, wantOpcodeCycles[op])
+		}
+	}
+}
+
+func TestCBOpcodeCycles(t *testing.T) {
+	if len(CBOpcodeCycles) != 0x100 {
+		t.Fatalf("%d entries in CBOpcodeCycles, want 256", len(CBOpcodeCycles))
+	}
+	for op := range CBOpcodeCycles {
+		// Prefix included: 2 for registers, 4 to read and write [HL] and 3
+		// for BIT n,[HL] which only reads it.
+		want := 2
+		if op & 7 == 6 {
+			want = 4
+			if op >= 0x40 && op < 0x80 {
+				want = 3
+			}
+		}
+		if CBOpcodeCycles[op] != want {
+			t.Errorf("CBOpcodeCycles[%#02x] (%s) = %d, want %d", op, PrefixInstrDebugLookup[op], CBOpcodeCycles[op], want)
+		}
+		if got := CBOpcodes[op]; got.Cycles != want || got.CyclesNotTaken != want {
+			t.Errorf("CBOpcodes[%#02x] takes %d/%d M-cycles, want %d", op, got.Cycles, got.CyclesNotTaken, want)
+		}
+	}
+}
+
+func TestOpcodeFlags(t *testing.T) {
+	tests := []struct {
+		op    int
+		flags string
+	}{
+		{0x00, "----"},
+		{0x03, "----"}, // INC BC
+		{0x04, "Z0H-"}, // INC B
+		{0x05, "Z1H-"}, // DEC B
+		{0x34, "Z0H-"}, // INC [HL]
+		{0x35, "Z1H-"}, // DEC [HL]
+		{0x07, "000C"}, // RLCA
+		{0x09, "-0HC"}, // ADD HL,BC
+		{0x27, "Z-0C"}, // DAA
+		{0x2F, "-11-"}, // CPL
+		{0x37, "-001"}, // SCF
+		{0x3F, "-00C"}, // CCF
+		{0x86, "Z0HC"}, // ADD A,[HL]
+		{0x96, "Z1HC"}, // SUB A,[HL]
+		{0xA6, "Z010"}, // AND A,[HL]
+		{0xAE, "Z000"}, // XOR A,[HL]
+		{0xBE, "Z1HC"}, // CP A,[HL]
+		{0xE8, "00HC"}, // ADD SP,e8
+		{0xF8, "00HC"}, // LD HL,SP+e8
+		{0xF1, "ZNHC"}, // POP AF
+		{0xC1, "----"}, // POP BC
+	}
+	for _, test := range tests {
+		if got := Opcodes[test.op].Flags; got != test.flags {
+			t.Errorf("Opcodes[%#02x] (%s) flags = %q, want %q", test.op, InstrDebugLookup[test.op], got, test.flags)
+		}
+	}
+	for op := range CBOpcodes {
+		var want string
+		switch {
+		case op < 0x30 || op >= 0x38 && op < 0x40:
+			want = "Z00C" // rotates and shifts
+		case op < 0x38:
+			want = "Z000" // SWAP
+		case op < 0x80:
+			want = "Z01-" // BIT
+		default:
+			want = "----" // RES, SET
+		}
+		if got := CBOpcodes[op].Flags; got != want {
+			t.Errorf("CBOpcodes[%#02x] (%s) flags = %q, want %q", op, PrefixInstrDebugLookup[op], got, want)
+		}
+	}
+}
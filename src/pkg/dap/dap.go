@@ -0,0 +1,157 @@
+// Debug Adapter Protocol server, for debugging from editors.
+//
+// Messages are JSON bodies behind a Content-Length header, see
+// https://microsoft.github.io/debug-adapter-protocol/specification. There is
+// a single thread (the CPU), its stack frames come from the shadow call
+// stack. Source breakpoints need a symbol file and the assembly sources, both
+// given in the launch or attach arguments:
+//
+//	{
+//		"program": "game.gb",         // launch only
+//		"symbols": "game.sym",        // or the .map file
+//		"sources": ["src/main.asm"],  // defaults to the sources next to the symbols
+//		"stopOnEntry": true
+//	}
+package dap
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gopherboy/pkg/gameboy"
+	"io"
+	"log"
+	"net"
+	"net/textproto"
+	"strconv"
+)
+
+// Instructions executed between two checks for requests from the client
+// while the target runs.
+const runBatchSize = 1024
+
+type Server struct {
+	// Machine used by attach requests, may be nil.
+	gb *gameboy.GB
+	// Creates the machine for launch requests from the program path, may be
+	// nil.
+	launch func(program string) (*gameboy.GB, error)
+}
+
+func New(gb *gameboy.GB, launch func(program string) (*gameboy.GB, error)) *Server {
+	return &Server{gb: gb, launch: launch}
+}
+
+// ListenAndServe accepts editor connections on addr, one at a time.
+func (s *Server) ListenAndServe(addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	defer l.Close()
+	log.Printf("dap: listening on %s", l.Addr())
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+		log.Printf("dap: client connected from %s", conn.RemoteAddr())
+		err = s.Serve(conn)
+		conn.Close()
+		if err != nil && !errors.Is(err, io.EOF) {
+			log.Printf("dap: %v", err)
+		}
+	}
+}
+
+type request struct {
+	Seq       int             `json:"seq"`
+	Type      string          `json:"type"`
+	Command   string          `json:"command"`
+	Arguments json.RawMessage `json:"arguments"`
+}
+
+type response struct {
+	Seq        int    `json:"seq"`
+	Type       string `json:"type"`
+	RequestSeq int    `json:"request_seq"`
+	Success    bool   `json:"success"`
+	Command    string `json:"command"`
+	Message    string `json:"message,omitempty"`
+	Body       any    `json:"body,omitempty"`
+}
+
+type event struct {
+	Seq   int    `json:"seq"`
+	Type  string `json:"type"`
+	Event string `json:"event"`
+	Body  any    `json:"body,omitempty"`
+}
+
+// Serve handles a single client until it disconnects.
+func (s *Server) Serve(conn io.ReadWriter) error {
+	sess := newSession(s, conn)
+	errc := make(chan error, 1)
+	go func() {
+		errc <- readRequests(conn, sess.requests)
+		close(sess.requests)
+	}()
+	for {
+		var req *request
+		var ok bool
+		if sess.running {
+			select {
+			case req, ok = <-sess.requests:
+			default:
+				if err := sess.runBatch(); err != nil {
+					return err
+				}
+				continue
+			}
+		} else {
+			req, ok = <-sess.requests
+		}
+		if !ok {
+			return <-errc
+		}
+		done, err := sess.handle(req)
+		if err != nil || done {
+			return err
+		}
+	}
+}
+
+func readRequests(r io.Reader, requests chan<- *request) error {
+	tr := textproto.NewReader(bufio.NewReader(r))
+	for {
+		header, err := tr.ReadMIMEHeader()
+		if err != nil {
+			return err
+		}
+		length, err := strconv.Atoi(header.Get("Content-Length"))
+		if err != nil || length < 0 {
+			return fmt.Errorf("invalid Content-Length %q", header.Get("Content-Length"))
+		}
+		body := make([]byte, length)
+		if _, err := io.ReadFull(tr.R, body); err != nil {
+			return err
+		}
+		req := &request{}
+		if err := json.Unmarshal(body, req); err != nil {
+			return fmt.Errorf("invalid message: %w", err)
+		}
+		if req.Type == "request" {
+			requests <- req
+		}
+	}
+}
+
+func writeMessage(w io.Writer, msg any) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "Content-Length: %d\r\n\r\n%s", len(body), body)
+	return err
+}
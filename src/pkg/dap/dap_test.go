@@ -0,0 +1,283 @@
+package dap
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"gopherboy/pkg/gameboy"
+	"io"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+// main.asm assembled at 0x0100.
+var testCode = map[uint16][]byte{
+	// ld b, 1 / call Func / jr .loop
+	0x0100: {0x06, 0x01, 0xCD, 0x10, 0x01, 0x18, 0xFB},
+	// ld c, 2 / ret
+	0x0110: {0x0E, 0x02, 0xC9},
+}
+
+const testSource = `SECTION "Main", ROM0[$100]
+Main:
+	ld b, 1
+.loop:
+	call Func
+	jr .loop
+
+SECTION "Func", ROM0[$110]
+Func:
+	ld c, 2
+	ret
+`
+
+const testSymbols = `; File generated by rgblink
+00:0100 Main
+00:0102 Main.loop
+00:0110 Func
+`
+
+// Builds a machine for attach requests, and writes its symbols and source to
+// a temporary directory.
+func newTestGB(t *testing.T) (*gameboy.GB, string) {
+	t.Helper()
+	dir := t.TempDir()
+	rom := make([]byte, 0x8000)
+	for addr, code := range testCode {
+		copy(rom[addr:], code)
+	}
+	files := map[string][]byte{
+		"game.sym": []byte(testSymbols),
+		"main.asm": []byte(testSource),
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	gb, err := gameboy.NewGBFromROM(rom)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return gb, dir
+}
+
+type message struct {
+	Type       string          `json:"type"`
+	Event      string          `json:"event"`
+	RequestSeq int             `json:"request_seq"`
+	Success    bool            `json:"success"`
+	Message    string          `json:"message"`
+	Body       json.RawMessage `json:"body"`
+}
+
+type client struct {
+	t   *testing.T
+	w   io.Writer
+	r   *textproto.Reader
+	seq int
+	// Events received while waiting for responses.
+	events []message
+	done   chan error
+}
+
+// Starts a session with s over a pair of pipes.
+func connect(t *testing.T, s *Server) *client {
+	cr, sw := io.Pipe()
+	sr, cw := io.Pipe()
+	c := &client{t: t, w: cw, r: textproto.NewReader(bufio.NewReader(cr)), done: make(chan error, 1)}
+	go func() {
+		err := s.Serve(struct {
+			io.Reader
+			io.Writer
+		}{sr, sw})
+		sw.Close()
+		c.done <- err
+	}()
+	t.Cleanup(func() { cw.Close() })
+	return c
+}
+
+func (c *client) read() message {
+	c.t.Helper()
+	header, err := c.r.ReadMIMEHeader()
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	length, err := strconv.Atoi(header.Get("Content-Length"))
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(c.r.R, body); err != nil {
+		c.t.Fatal(err)
+	}
+	var msg message
+	if err := json.Unmarshal(body, &msg); err != nil {
+		c.t.Fatal(err)
+	}
+	return msg
+}
+
+// Sends a request and decodes the body of its response into body, which may
+// be nil.
+func (c *client) call(command string, args any, body any) {
+	c.t.Helper()
+	c.seq++
+	data, err := json.Marshal(map[string]any{"seq": c.seq, "type": "request", "command": command, "arguments": args})
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	if _, err := fmt.Fprintf(c.w, "Content-Length: %d\r\n\r\n%s", len(data), data); err != nil {
+		c.t.Fatal(err)
+	}
+	for {
+		msg := c.read()
+		if msg.Type == "event" {
+			c.events = append(c.events, msg)
+			continue
+		}
+		if msg.RequestSeq != c.seq {
+			c.t.Fatalf("response to request %d, want %d", msg.RequestSeq, c.seq)
+		}
+		if !msg.Success {
+			c.t.Fatalf("%s failed: %s", command, msg.Message)
+		}
+		if body != nil {
+			if err := json.Unmarshal(msg.Body, body); err != nil {
+				c.t.Fatal(err)
+			}
+		}
+		return
+	}
+}
+
+// Returns the body of the next event called name, received or to come.
+func (c *client) event(name string) json.RawMessage {
+	c.t.Helper()
+	for {
+		for i, ev := range c.events {
+			if ev.Event == name {
+				c.events = append(c.events[:i], c.events[i+1:]...)
+				return ev.Body
+			}
+		}
+		msg := c.read()
+		if msg.Type == "event" {
+			c.events = append(c.events, msg)
+		}
+	}
+}
+
+type stoppedBody struct {
+	Reason string `json:"reason"`
+}
+
+func (c *client) expectStop(reason string) {
+	c.t.Helper()
+	var body stoppedBody
+	if err := json.Unmarshal(c.event("stopped"), &body); err != nil {
+		c.t.Fatal(err)
+	}
+	if body.Reason != reason {
+		c.t.Fatalf("stopped for %q, want %q", body.Reason, reason)
+	}
+}
+
+func (c *client) disconnect() {
+	c.t.Helper()
+	c.call("disconnect", nil, nil)
+	if err := <-c.done; err != nil {
+		c.t.Fatalf("Serve: %v", err)
+	}
+}
+
+func TestSession(t *testing.T) {
+	gb, dir := newTestGB(t)
+	c := connect(t, New(gb, nil))
+	source := filepath.Join(dir, "main.asm")
+
+	var caps map[string]bool
+	c.call("initialize", map[string]any{"adapterID": "gopherboy"}, &caps)
+	if !caps["supportsConfigurationDoneRequest"] || !caps["supportsDisassembleRequest"] {
+		t.Errorf("capabilities %v", caps)
+	}
+	c.event("initialized")
+	c.call("attach", map[string]any{"symbols": filepath.Join(dir, "game.sym"), "stopOnEntry": true}, nil)
+
+	var bps struct {
+		Breakpoints []breakpoint `json:"breakpoints"`
+	}
+	c.call("setBreakpoints", map[string]any{
+		"source": map[string]any{"path": source},
+		// Line 9 holds the label, the breakpoint slides down to line 10.
+		"breakpoints": []any{map[string]any{"line": 9}, map[string]any{"line": 20}},
+	}, &bps)
+	if len(bps.Breakpoints) != 2 {
+		t.Fatalf("got %d breakpoints, want 2", len(bps.Breakpoints))
+	}
+	if bp := bps.Breakpoints[0]; !bp.Verified || bp.Line != 10 || bp.InstructionReference != "0x0110" {
+		t.Errorf("breakpoint at line 9 = %+v, want verified at line 10, 0x0110", bp)
+	}
+	if bp := bps.Breakpoints[1]; bp.Verified {
+		t.Errorf("breakpoint past the end of the file = %+v, want unverified", bp)
+	}
+
+	c.call("configurationDone", nil, nil)
+	c.expectStop("entry")
+	c.call("continue", map[string]any{"threadId": threadID}, nil)
+	c.expectStop("breakpoint")
+	if gb.CPU.PC != 0x0110 {
+		t.Fatalf("stopped at %#04x, want 0x0110", gb.CPU.PC)
+	}
+
+	var trace struct {
+		StackFrames []stackFrame `json:"stackFrames"`
+	}
+	c.call("stackTrace", map[string]any{"threadId": threadID}, &trace)
+	want := []struct {
+		name string
+		line int
+		ref  string
+	}{
+		{"Func", 10, "0x0110"},
+		{"Main.loop", 5, "0x0102"},
+	}
+	if len(trace.StackFrames) != len(want) {
+		t.Fatalf("got %d stack frames, want %d: %+v", len(trace.StackFrames), len(want), trace.StackFrames)
+	}
+	for i, w := range want {
+		f := trace.StackFrames[i]
+		if f.Name != w.name || f.Line != w.line || f.InstructionPointerReference != w.ref || f.Source == nil || f.Source.Path != source {
+			t.Errorf("frame %d = %+v, want %s at %s:%d (%s)", i, f, w.name, source, w.line, w.ref)
+		}
+	}
+	c.disconnect()
+}
+
+func TestDisassembleEndOfMemory(t *testing.T) {
+	gb, _ := newTestGB(t)
+	// CALL at 0xFFFE, its operand would run past the end of memory.
+	gb.MMU.Poke(0xFFFE, 0xCD)
+	c := connect(t, New(gb, nil))
+	c.call("attach", map[string]any{}, nil)
+	var result struct {
+		Instructions []disassembledInstruction `json:"instructions"`
+	}
+	c.call("disassemble", map[string]any{"memoryReference": "0xfffe", "instructionCount": 4}, &result)
+	if len(result.Instructions) != 4 {
+		t.Fatalf("got %d instructions, want 4", len(result.Instructions))
+	}
+	if in := result.Instructions[0]; in.Address != "0xfffe" || in.PresentationHint == "invalid" {
+		t.Errorf("first instruction = %+v, want the call at 0xfffe", in)
+	}
+	for _, in := range result.Instructions[1:] {
+		if in.PresentationHint != "invalid" {
+			t.Errorf("instruction %+v past the end of memory, want invalid", in)
+		}
+	}
+	c.disconnect()
+}
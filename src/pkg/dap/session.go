@@ -0,0 +1,641 @@
+package dap
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gopherboy/pkg/gameboy"
+	"gopherboy/pkg/symbols"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const threadID = 1
+
+// Variable references of the two scopes.
+const (
+	registersRef = 1
+	symbolsRef   = 2
+)
+
+// Lines a source breakpoint may move down to reach code.
+const maxBreakpointSlide = 16
+
+var errNotStarted = errors.New("no program launched or attached")
+
+type session struct {
+	*Server
+	conn     io.Writer
+	requests chan *request
+	seq      int
+	// Events to send once the response to the current request is out.
+	pending []event
+
+	gb      *gameboy.GB
+	sources *sourceMap
+
+	// Breakpoint addresses by source file, and instruction breakpoints.
+	sourceBreakpoints map[string][]uint16
+	instrBreakpoints  []uint16
+	breakpoints       map[uint16]bool
+	stopOnEntry       bool
+
+	running bool
+	// Instructions executed since the target was resumed.
+	steps int
+	// Reports whether a step request completed after executing opcode, nil
+	// when continuing.
+	stepDone func(opcode byte) bool
+}
+
+func newSession(s *Server, conn io.Writer) *session {
+	return &session{
+		Server:            s,
+		conn:              conn,
+		requests:          make(chan *request, 16),
+		sourceBreakpoints: map[string][]uint16{},
+		breakpoints:       map[uint16]bool{},
+	}
+}
+
+func (sess *session) nextSeq() int {
+	sess.seq++
+	return sess.seq
+}
+
+func (sess *session) sendEvent(name string, body any) error {
+	return writeMessage(sess.conn, event{Seq: sess.nextSeq(), Type: "event", Event: name, Body: body})
+}
+
+func (sess *session) queueEvent(name string, body any) {
+	sess.pending = append(sess.pending, event{Type: "event", Event: name, Body: body})
+}
+
+func (sess *session) output(format string, args ...any) {
+	sess.queueEvent("output", map[string]any{"category": "console", "output": fmt.Sprintf(format, args...) + "\n"})
+}
+
+// Answers a request and sends the events it queued. Returns whether the
+// session is over.
+func (sess *session) handle(req *request) (bool, error) {
+	body, err := sess.dispatch(req)
+	resp := response{
+		Seq:        sess.nextSeq(),
+		Type:       "response",
+		RequestSeq: req.Seq,
+		Success:    err == nil,
+		Command:    req.Command,
+		Body:       body,
+	}
+	if err != nil {
+		resp.Message = err.Error()
+	}
+	if err := writeMessage(sess.conn, resp); err != nil {
+		return false, err
+	}
+	for _, ev := range sess.pending {
+		ev.Seq = sess.nextSeq()
+		if err := writeMessage(sess.conn, ev); err != nil {
+			return false, err
+		}
+	}
+	sess.pending = nil
+	return req.Command == "disconnect", nil
+}
+
+func (sess *session) dispatch(req *request) (any, error) {
+	switch req.Command {
+	case "initialize":
+		sess.queueEvent("initialized", nil)
+		return map[string]any{
+			"supportsConfigurationDoneRequest": true,
+			"supportsInstructionBreakpoints":   true,
+			"supportsDisassembleRequest":       true,
+			"supportsReadMemoryRequest":        true,
+			"supportsSteppingGranularity":      true,
+			"supportsEvaluateForHovers":        true,
+		}, nil
+	case "launch", "attach":
+		return nil, sess.start(req)
+	case "disconnect":
+		return nil, nil
+	}
+	if sess.gb == nil {
+		return nil, errNotStarted
+	}
+	switch req.Command {
+	case "setBreakpoints":
+		return sess.setBreakpoints(req.Arguments)
+	case "setInstructionBreakpoints":
+		return sess.setInstructionBreakpoints(req.Arguments)
+	case "setExceptionBreakpoints":
+		return map[string]any{"breakpoints": []any{}}, nil
+	case "configurationDone":
+		if sess.stopOnEntry {
+			sess.queueStopped("entry", "")
+		} else {
+			sess.resume(nil)
+		}
+		return nil, nil
+	case "threads":
+		return map[string]any{"threads": []any{map[string]any{"id": threadID, "name": "SM83"}}}, nil
+	case "stackTrace":
+		return sess.stackTrace()
+	case "scopes":
+		return map[string]any{"scopes": []any{
+			map[string]any{"name": "Registers", "variablesReference": registersRef},
+			map[string]any{"name": "Symbols", "variablesReference": symbolsRef},
+		}}, nil
+	case "variables":
+		return sess.variables(req.Arguments)
+	case "evaluate":
+		return sess.evaluate(req.Arguments)
+	case "continue":
+		sess.resume(nil)
+		return map[string]any{"allThreadsContinued": true}, nil
+	case "next":
+		sess.stepOver()
+		return nil, nil
+	case "stepIn":
+		sess.resume(func(byte) bool { return true })
+		return nil, nil
+	case "stepOut":
+		sess.stepOut()
+		return nil, nil
+	case "pause":
+		if sess.running {
+			sess.running = false
+			sess.queueStopped("pause", "")
+		}
+		return nil, nil
+	case "disassemble":
+		return sess.disassemble(req.Arguments)
+	case "readMemory":
+		return sess.readMemory(req.Arguments)
+	}
+	return nil, fmt.Errorf("unsupported request %q", req.Command)
+}
+
+type startArgs struct {
+	Program     string   `json:"program"`
+	Symbols     string   `json:"symbols"`
+	Sources     []string `json:"sources"`
+	StopOnEntry bool     `json:"stopOnEntry"`
+}
+
+func (sess *session) start(req *request) error {
+	var args startArgs
+	if err := json.Unmarshal(req.Arguments, &args); err != nil {
+		return err
+	}
+	if req.Command == "launch" {
+		if sess.launch == nil || args.Program == "" {
+			return errors.New("launch needs a program")
+		}
+		gb, err := sess.launch(args.Program)
+		if err != nil {
+			return err
+		}
+		sess.gb = gb
+	} else {
+		if sess.Server.gb == nil {
+			return errors.New("nothing to attach to")
+		}
+		sess.gb = sess.Server.gb
+	}
+	sess.stopOnEntry = args.StopOnEntry
+	if args.Symbols != "" {
+		if err := sess.gb.LoadSymbols(args.Symbols); err != nil {
+			sess.gb = nil
+			return err
+		}
+	}
+	paths := args.Sources
+	if len(paths) == 0 && args.Symbols != "" {
+		paths = findSources(filepath.Dir(args.Symbols))
+	}
+	sess.sources = buildSourceMap(sess.gb, sess.gb.Symbols, paths)
+	if sess.gb.Symbols != nil {
+		sess.output("Loaded %d symbols, mapped %d source lines", sess.gb.Symbols.Len(), sess.sources.Len())
+	}
+	return nil
+}
+
+type source struct {
+	Name string `json:"name,omitempty"`
+	Path string `json:"path,omitempty"`
+}
+
+func newSource(path string) *source {
+	return &source{Name: filepath.Base(path), Path: path}
+}
+
+type setBreakpointsArgs struct {
+	Source      source `json:"source"`
+	Breakpoints []struct {
+		Line int `json:"line"`
+	} `json:"breakpoints"`
+}
+
+type breakpoint struct {
+	Verified             bool    `json:"verified"`
+	Message              string  `json:"message,omitempty"`
+	Source               *source `json:"source,omitempty"`
+	Line                 int     `json:"line,omitempty"`
+	InstructionReference string  `json:"instructionReference,omitempty"`
+}
+
+// Replaces the breakpoints of a source file.
+func (sess *session) setBreakpoints(raw json.RawMessage) (any, error) {
+	var args setBreakpointsArgs
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return nil, err
+	}
+	path := normalizePath(args.Source.Path)
+	var addrs []uint16
+	result := []breakpoint{}
+	for _, bp := range args.Breakpoints {
+		addr, line, ok := sess.sources.Addr(path, bp.Line, maxBreakpointSlide)
+		if !ok {
+			result = append(result, breakpoint{Message: "no code found at this line", Line: bp.Line})
+			continue
+		}
+		addrs = append(addrs, addr)
+		result = append(result, breakpoint{
+			Verified:             true,
+			Source:               newSource(path),
+			Line:                 line,
+			InstructionReference: formatAddr(addr),
+		})
+	}
+	sess.sourceBreakpoints[path] = addrs
+	sess.updateBreakpoints()
+	return map[string]any{"breakpoints": result}, nil
+}
+
+type setInstructionBreakpointsArgs struct {
+	Breakpoints []struct {
+		InstructionReference string `json:"instructionReference"`
+		Offset               int    `json:"offset"`
+	} `json:"breakpoints"`
+}
+
+// Replaces the breakpoints set from the disassembly view.
+func (sess *session) setInstructionBreakpoints(raw json.RawMessage) (any, error) {
+	var args setInstructionBreakpointsArgs
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return nil, err
+	}
+	sess.instrBreakpoints = nil
+	result := []breakpoint{}
+	for _, bp := range args.Breakpoints {
+		addr, err := parseAddr(bp.InstructionReference, bp.Offset)
+		if err != nil {
+			result = append(result, breakpoint{Message: err.Error()})
+			continue
+		}
+		sess.instrBreakpoints = append(sess.instrBreakpoints, addr)
+		result = append(result, breakpoint{Verified: true, InstructionReference: formatAddr(addr)})
+	}
+	sess.updateBreakpoints()
+	return map[string]any{"breakpoints": result}, nil
+}
+
+func (sess *session) updateBreakpoints() {
+	sess.breakpoints = map[uint16]bool{}
+	for _, addrs := range sess.sourceBreakpoints {
+		for _, addr := range addrs {
+			sess.breakpoints[addr] = true
+		}
+	}
+	for _, addr := range sess.instrBreakpoints {
+		sess.breakpoints[addr] = true
+	}
+}
+
+func (sess *session) resume(done func(opcode byte) bool) {
+	sess.running = true
+	sess.steps = 0
+	sess.stepDone = done
+}
+
+// Steps over calls, a single instruction otherwise.
+func (sess *session) stepOver() {
+	cpu := sess.gb.CPU
+	if !gameboy.IsCall(sess.gb.MMU.Peek(cpu.PC)) {
+		sess.resume(func(byte) bool { return true })
+		return
+	}
+	_, ret := cpu.DecodeAt(cpu.PC)
+	sp := cpu.SP.Value()
+	sess.resume(func(byte) bool {
+		return cpu.PC == ret && cpu.SP.Value() >= sp
+	})
+}
+
+// Runs until the current function returns.
+func (sess *session) stepOut() {
+	cpu := sess.gb.CPU
+	sp := cpu.SP.Value()
+	sess.resume(func(opcode byte) bool {
+		return gameboy.IsReturn(opcode) && cpu.SP.Value() > sp
+	})
+}
+
+// Executes a batch of instructions, stopping at breakpoints, errors or the
+// end of a step.
+func (sess *session) runBatch() error {
+	gb := sess.gb
+	for i := 0; i < runBatchSize; i++ {
+		if sess.steps > 0 && sess.breakpoints[gb.CPU.PC] {
+			return sess.stop("breakpoint", "")
+		}
+		opcode := gb.MMU.Peek(gb.CPU.PC)
+		_, err := gb.Step()
+		sess.steps++
+		var hit *gameboy.WatchpointHit
+		switch {
+		case errors.As(err, &hit):
+			return sess.stop("data breakpoint", err.Error())
+		case err != nil:
+			return sess.stop("exception", err.Error())
+		}
+		if sess.stepDone != nil && sess.stepDone(opcode) {
+			return sess.stop("step", "")
+		}
+	}
+	return nil
+}
+
+func (sess *session) stop(reason, text string) error {
+	sess.running = false
+	body := map[string]any{"reason": reason, "threadId": threadID, "allThreadsStopped": true}
+	if text != "" {
+		body["text"] = text
+	}
+	return sess.sendEvent("stopped", body)
+}
+
+func (sess *session) queueStopped(reason, text string) {
+	body := map[string]any{"reason": reason, "threadId": threadID, "allThreadsStopped": true}
+	if text != "" {
+		body["text"] = text
+	}
+	sess.queueEvent("stopped", body)
+}
+
+type stackFrame struct {
+	ID                          int     `json:"id"`
+	Name                        string  `json:"name"`
+	Source                      *source `json:"source,omitempty"`
+	Line                        int     `json:"line"`
+	Column                      int     `json:"column"`
+	InstructionPointerReference string  `json:"instructionPointerReference"`
+}
+
+// Frame 0 is at PC, the others at the call sites from the shadow call stack.
+func (sess *session) stackTrace() (any, error) {
+	frames := []stackFrame{sess.frameAt(0, sess.gb.CPU.PC)}
+	for i, f := range sess.gb.CPU.Backtrace() {
+		frames = append(frames, sess.frameAt(i + 1, f.CallSite))
+	}
+	return map[string]any{"stackFrames": frames, "totalFrames": len(frames)}, nil
+}
+
+func (sess *session) frameAt(id int, addr uint16) stackFrame {
+	frame := stackFrame{
+		ID:                          id,
+		Name:                        sess.gb.Symbols.Format(sess.gb.MMU.BankAt(addr), addr),
+		InstructionPointerReference: formatAddr(addr),
+	}
+	if loc, ok := sess.sources.Line(addr); ok {
+		frame.Source = newSource(loc.path)
+		frame.Line = loc.line
+		frame.Column = 1
+	}
+	return frame
+}
+
+type variable struct {
+	Name               string `json:"name"`
+	Value              string `json:"value"`
+	VariablesReference int    `json:"variablesReference"`
+	MemoryReference    string `json:"memoryReference,omitempty"`
+}
+
+func (sess *session) variables(raw json.RawMessage) (any, error) {
+	var args struct {
+		VariablesReference int `json:"variablesReference"`
+	}
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return nil, err
+	}
+	vars := []variable{}
+	switch args.VariablesReference {
+	case registersRef:
+		vars = sess.registers()
+	case symbolsRef:
+		for _, sym := range sess.gb.Symbols.Symbols() {
+			// Only RAM and IO symbols name variables, ROM symbols are code
+			// or constant data.
+			if sym.Addr < 0x8000 || sym.Bank != sess.gb.MMU.BankAt(sym.Addr) {
+				continue
+			}
+			vars = append(vars, sess.symbolVariable(sym))
+		}
+	}
+	return map[string]any{"variables": vars}, nil
+}
+
+func (sess *session) registers() []variable {
+	cpu := sess.gb.CPU
+	byteReg := func(name string, v byte) variable {
+		return variable{Name: name, Value: fmt.Sprintf("$%02x", v)}
+	}
+	wordReg := func(name string, v uint16) variable {
+		return variable{Name: name, Value: fmt.Sprintf("$%04x", v), MemoryReference: formatAddr(v)}
+	}
+	flag := func(name string, idx uint8) variable {
+		return variable{Name: name, Value: strconv.Itoa(int(cpu.AF.Lo() >> idx & 1))}
+	}
+	pc := wordReg("PC", cpu.PC)
+	if label, ok := sess.gb.Label(cpu.PC); ok {
+		pc.Value += " (" + label + ")"
+	}
+	return []variable{
+		byteReg("A", cpu.AF.Hi()), byteReg("F", cpu.AF.Lo()),
+		byteReg("B", cpu.BC.Hi()), byteReg("C", cpu.BC.Lo()),
+		byteReg("D", cpu.DE.Hi()), byteReg("E", cpu.DE.Lo()),
+		byteReg("H", cpu.HL.Hi()), byteReg("L", cpu.HL.Lo()),
+		wordReg("AF", cpu.AF.Value()), wordReg("BC", cpu.BC.Value()),
+		wordReg("DE", cpu.DE.Value()), wordReg("HL", cpu.HL.Value()),
+		wordReg("SP", cpu.SP.Value()), pc,
+		flag("ZF", gameboy.Z_IDX), flag("NF", gameboy.N_IDX),
+		flag("HF", gameboy.H_IDX), flag("CF", gameboy.C_IDX),
+	}
+}
+
+func (sess *session) symbolVariable(sym symbols.Symbol) variable {
+	return variable{
+		Name:            sym.Name,
+		Value:           fmt.Sprintf("$%02x", sess.gb.MMU.Peek(sym.Addr)),
+		MemoryReference: formatAddr(sym.Addr),
+	}
+}
+
+// Evaluates a register or symbol name, for hovers and the watch view.
+func (sess *session) evaluate(raw json.RawMessage) (any, error) {
+	var args struct {
+		Expression string `json:"expression"`
+	}
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return nil, err
+	}
+	expr := strings.TrimSpace(args.Expression)
+	for _, v := range sess.registers() {
+		if strings.EqualFold(v.Name, expr) {
+			return map[string]any{"result": v.Value, "variablesReference": 0, "memoryReference": v.MemoryReference}, nil
+		}
+	}
+	if sym, ok := sess.gb.Symbols.Lookup(expr); ok {
+		v := sess.symbolVariable(sym)
+		return map[string]any{"result": v.Value, "variablesReference": 0, "memoryReference": v.MemoryReference}, nil
+	}
+	return nil, fmt.Errorf("unknown register or symbol %q", expr)
+}
+
+type disassembleArgs struct {
+	MemoryReference   string `json:"memoryReference"`
+	Offset            int    `json:"offset"`
+	InstructionOffset int    `json:"instructionOffset"`
+	InstructionCount  int    `json:"instructionCount"`
+}
+
+type disassembledInstruction struct {
+	Address          string  `json:"address"`
+	InstructionBytes string  `json:"instructionBytes,omitempty"`
+	Instruction      string  `json:"instruction"`
+	Symbol           string  `json:"symbol,omitempty"`
+	Location         *source `json:"location,omitempty"`
+	Line             int     `json:"line,omitempty"`
+	PresentationHint string  `json:"presentationHint,omitempty"`
+}
+
+func (sess *session) disassemble(raw json.RawMessage) (any, error) {
+	var args disassembleArgs
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return nil, err
+	}
+	base, err := parseAddr(args.MemoryReference, args.Offset)
+	if err != nil {
+		return nil, err
+	}
+	result := []disassembledInstruction{}
+	if args.InstructionOffset < 0 {
+		// Decoding backwards is ambiguous, so the instructions before base
+		// are decoded forward from far enough back to find the count. An
+		// instruction is at most 3 bytes long.
+		var before []int
+		for addr := max(int(base) + args.InstructionOffset * 3, 0); addr < int(base); {
+			before = append(before, addr)
+			addr = sess.nextAddr(addr)
+		}
+		before = before[max(len(before) + args.InstructionOffset, 0):]
+		// Nothing exists below address 0, but the client expects exactly
+		// the requested count.
+		for i := len(before); i < -args.InstructionOffset; i++ {
+			result = append(result, disassembledInstruction{Address: formatAddr(0), Instruction: "??", PresentationHint: "invalid"})
+		}
+		for _, addr := range before {
+			result = append(result, sess.disassembleAt(uint16(addr)))
+		}
+	}
+	addr := int(base)
+	for i := 0; i < args.InstructionOffset && addr <= 0xFFFF; i++ {
+		addr = sess.nextAddr(addr)
+	}
+	for len(result) < args.InstructionCount {
+		if addr > 0xFFFF {
+			result = append(result, disassembledInstruction{Address: formatAddr(0xFFFF), Instruction: "??", PresentationHint: "invalid"})
+			continue
+		}
+		result = append(result, sess.disassembleAt(uint16(addr)))
+		addr = sess.nextAddr(addr)
+	}
+	result = result[:min(len(result), args.InstructionCount)]
+	return map[string]any{"instructions": result}, nil
+}
+
+// Address of the instruction following the one at addr. Unlike the address
+// DecodeAt returns, it goes past 0xFFFF instead of wrapping around, so the
+// end of memory can be detected.
+func (sess *session) nextAddr(addr int) int {
+	_, next := sess.gb.CPU.DecodeAt(uint16(addr))
+	return addr + int(next - uint16(addr))
+}
+
+func (sess *session) disassembleAt(addr uint16) disassembledInstruction {
+	cpu := sess.gb.CPU
+	ii, next := cpu.DecodeAt(addr)
+	raw := make([]byte, 0, 3)
+	for a := addr; a != next; a++ {
+		raw = append(raw, sess.gb.MMU.Peek(a))
+	}
+	instr := disassembledInstruction{
+		Address:          formatAddr(addr),
+		InstructionBytes: strings.ToUpper(hex.EncodeToString(raw)),
+		Instruction:      ii.Text(cpu),
+	}
+	instr.Symbol, _ = sess.gb.SymbolAt(addr)
+	if loc, ok := sess.sources.Line(addr); ok {
+		instr.Location = newSource(loc.path)
+		instr.Line = loc.line
+	}
+	return instr
+}
+
+func (sess *session) readMemory(raw json.RawMessage) (any, error) {
+	var args struct {
+		MemoryReference string `json:"memoryReference"`
+		Offset          int    `json:"offset"`
+		Count           int    `json:"count"`
+	}
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return nil, err
+	}
+	addr, err := parseAddr(args.MemoryReference, args.Offset)
+	if err != nil {
+		return nil, err
+	}
+	count := min(max(args.Count, 0), 0x10000 - int(addr))
+	data := make([]byte, count)
+	for i := range data {
+		data[i] = sess.gb.MMU.Peek(addr + uint16(i))
+	}
+	return map[string]any{
+		"address":         formatAddr(addr),
+		"data":            base64.StdEncoding.EncodeToString(data),
+		"unreadableBytes": args.Count - count,
+	}, nil
+}
+
+// Memory and instruction references are "0x" prefixed hex addresses.
+func formatAddr(addr uint16) string {
+	return fmt.Sprintf("0x%04x", addr)
+}
+
+func parseAddr(ref string, offset int) (uint16, error) {
+	v, err := strconv.ParseUint(ref, 0, 16)
+	if err != nil {
+		return 0, fmt.Errorf("invalid address %q", ref)
+	}
+	addr := int(v) + offset
+	if addr < 0 || addr > 0xFFFF {
+		return 0, fmt.Errorf("address %q%+d out of range", ref, offset)
+	}
+	return uint16(addr), nil
+}
@@ -0,0 +1,183 @@
+package dap
+
+/*
+Source line mapping for assembly sources.
+
+RGBDS symbol files only name addresses, they carry no line information. The
+map is rebuilt from the sources instead: each label definition is anchored
+at its symbol's address, and the instruction lines following it are matched
+one by one against the instructions decoded from memory. Mapping stops at
+the first directive, macro invocation or mnemonic mismatch, since the layout
+of the following bytes is unknown from there until the next label.
+*/
+import (
+	"bufio"
+	"gopherboy/pkg/gameboy"
+	"gopherboy/pkg/symbols"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+var sourceExtensions = map[string]bool{".asm": true, ".s": true, ".z80": true, ".inc": true}
+
+var mnemonics = map[string]bool{
+	"ADC": true, "ADD": true, "AND": true, "BIT": true, "CALL": true, "CCF": true,
+	"CP": true, "CPL": true, "DAA": true, "DEC": true, "DI": true, "EI": true,
+	"HALT": true, "INC": true, "JP": true, "JR": true, "LD": true, "LDD": true,
+	"LDH": true, "LDI": true, "NOP": true, "OR": true, "POP": true, "PUSH": true,
+	"RES": true, "RET": true, "RETI": true, "RL": true, "RLA": true, "RLC": true,
+	"RLCA": true, "RR": true, "RRA": true, "RRC": true, "RRCA": true, "RST": true,
+	"SBC": true, "SCF": true, "SET": true, "SLA": true, "SRA": true, "SRL": true,
+	"STOP": true, "SUB": true, "SWAP": true, "XOR": true,
+}
+
+// Mnemonics written differently in sources and in the decoding tables, e.g.
+// "ld [hl+], a" decodes as LDI.
+var loadMnemonics = map[string]bool{"LD": true, "LDH": true, "LDI": true, "LDD": true}
+
+type sourceLoc struct {
+	path string
+	line int
+}
+
+type sourceMap struct {
+	// Address of each mapped line, by file.
+	addrs map[string]map[int]uint16
+	// First line mapped to each address.
+	lines map[uint16]sourceLoc
+}
+
+// Finds the assembly sources below dir.
+func findSources(dir string) []string {
+	var paths []string
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() && sourceExtensions[strings.ToLower(filepath.Ext(path))] {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	return paths
+}
+
+func buildSourceMap(gb *gameboy.GB, syms *symbols.Table, paths []string) *sourceMap {
+	m := &sourceMap{addrs: map[string]map[int]uint16{}, lines: map[uint16]sourceLoc{}}
+	if syms == nil {
+		return m
+	}
+	for _, path := range paths {
+		// Unreadable sources simply stay unmapped.
+		m.addFile(gb, syms, normalizePath(path))
+	}
+	return m
+}
+
+func (m *sourceMap) addFile(gb *gameboy.GB, syms *symbols.Table, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	var scope string
+	var addr uint16
+	mapping := false
+	scanner := bufio.NewScanner(f)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		label, mnemonic, rest := splitSourceLine(scanner.Text())
+		if label != "" {
+			name := label
+			if strings.HasPrefix(label, ".") {
+				name = scope + label
+			} else {
+				scope, _, _ = strings.Cut(label, ".")
+			}
+			sym, ok := syms.Lookup(name)
+			mapping = ok && sym.Addr < 0x8000 && sym.Bank == gb.MMU.BankAt(sym.Addr)
+			addr = sym.Addr
+		}
+		if mnemonic == "" && rest == "" {
+			continue
+		}
+		if !mapping || !mnemonics[mnemonic] {
+			mapping = false
+			continue
+		}
+		ii, next := gb.CPU.DecodeAt(addr)
+		decoded := ii.Mnemonic()
+		if decoded != mnemonic && !(loadMnemonics[decoded] && loadMnemonics[mnemonic]) {
+			mapping = false
+			continue
+		}
+		if m.addrs[path] == nil {
+			m.addrs[path] = map[int]uint16{}
+		}
+		m.addrs[path][lineNo] = addr
+		if _, ok := m.lines[addr]; !ok {
+			m.lines[addr] = sourceLoc{path: path, line: lineNo}
+		}
+		addr = next
+	}
+	return scanner.Err()
+}
+
+// Splits a source line into its label definition, upper cased first token
+// and the remaining text. Comments are dropped.
+func splitSourceLine(line string) (label, mnemonic, rest string) {
+	line, _, _ = strings.Cut(line, ";")
+	first, rest := cutToken(line)
+	if before, ok := strings.CutSuffix(first, ":"); ok {
+		label = strings.TrimSuffix(before, ":")
+	} else if strings.HasPrefix(first, ".") {
+		// Local labels may leave out the colon.
+		label = first
+	}
+	if label != "" {
+		first, rest = cutToken(rest)
+	}
+	return label, strings.ToUpper(first), rest
+}
+
+// Splits off the first whitespace separated token.
+func cutToken(s string) (string, string) {
+	s = strings.TrimSpace(s)
+	i := strings.IndexFunc(s, unicode.IsSpace)
+	if i < 0 {
+		return s, ""
+	}
+	return s[:i], strings.TrimSpace(s[i:])
+}
+
+// Addr returns the address mapped to the first line at or after line, looking
+// at most maxLines ahead. The line actually used is returned with it.
+func (m *sourceMap) Addr(path string, line, maxLines int) (uint16, int, bool) {
+	lines := m.addrs[normalizePath(path)]
+	for l := line; l <= line + maxLines; l++ {
+		if addr, ok := lines[l]; ok {
+			return addr, l, true
+		}
+	}
+	return 0, 0, false
+}
+
+func (m *sourceMap) Line(addr uint16) (sourceLoc, bool) {
+	loc, ok := m.lines[addr]
+	return loc, ok
+}
+
+// Len returns the number of mapped lines.
+func (m *sourceMap) Len() int {
+	n := 0
+	for _, lines := range m.addrs {
+		n += len(lines)
+	}
+	return n
+}
+
+func normalizePath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return filepath.Clean(path)
+}
@@ -0,0 +1,430 @@
+// Interactive command-line debugger for the gameboy core.
+package debugger
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"gopherboy/pkg/gameboy"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+const helpText = `Commands:
+  step, s [n]          execute n instructions (default 1)
+  next, n              step over CALL and RST
+  finish, fin          run until the current subroutine returns
+  continue, c          run until a breakpoint is hit (Ctrl-C to pause)
+  break, b [addr] [if cond]
+                       set a (conditional) breakpoint at addr, or list breakpoints
+  ignore addr n        don't stop at the breakpoint at addr for its next n hits
+  delete, d [addr]     delete the breakpoint at addr, or all breakpoints
+  watch, w [r|w|rw|c] start[-end]
+                       stop after reads, writes, either, or value changes
+                       (default w) in start..end, or list watchpoints
+  unwatch id           delete a watchpoint
+  backtrace, bt        show the call stack
+  regs, r              show registers and flags
+  mem, x addr [len]    hex dump len bytes (default 64) starting at addr
+  disas, dis [addr] [n] disassemble n instructions (default 10) from addr or PC
+  help, h              show this help
+  quit, q              exit the debugger
+Addresses are hexadecimal, with or without a 0x or $ prefix, or labels from
+the symbol file. An empty line repeats the previous command. Conditions are
+expressions over registers, flags, labels and memory, e.g.
+"A == 0x3C && [wCounter] > 4 && ZF".
+`
+
+type breakpoint struct {
+	cond *Condition
+	// Times the breakpoint was reached with its condition true.
+	hits int
+	// Hits to skip before stopping.
+	ignore int
+}
+
+type Debugger struct {
+	gb  *gameboy.GB
+	in  *bufio.Scanner
+	out io.Writer
+
+	breakpoints map[uint16]*breakpoint
+	// Set from the signal handler to pause a running continue.
+	interrupted atomic.Bool
+}
+
+func New(gb *gameboy.GB, in io.Reader, out io.Writer) *Debugger {
+	return &Debugger{
+		gb:          gb,
+		in:          bufio.NewScanner(in),
+		out:         out,
+		breakpoints: map[uint16]*breakpoint{},
+	}
+}
+
+// Run reads and executes commands until quit or the end of input.
+func (d *Debugger) Run() error {
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+	go func() {
+		for range interrupt {
+			d.interrupted.Store(true)
+		}
+	}()
+
+	d.printLocation()
+	last := ""
+	for {
+		fmt.Fprint(d.out, "(gb) ")
+		if !d.in.Scan() {
+			return d.in.Err()
+		}
+		line := strings.TrimSpace(d.in.Text())
+		if line == "" {
+			line = last
+		}
+		last = line
+		args := strings.Fields(line)
+		if len(args) == 0 {
+			continue
+		}
+		if args[0] == "quit" || args[0] == "q" {
+			return nil
+		}
+		if err := d.execute(args[0], args[1:]); err != nil {
+			fmt.Fprintf(d.out, "%v\n", err)
+		}
+	}
+}
+
+func (d *Debugger) execute(cmd string, args []string) error {
+	switch cmd {
+	case "step", "s":
+		n := 1
+		if len(args) > 0 {
+			v, err := strconv.Atoi(args[0])
+			if err != nil || v < 1 {
+				return fmt.Errorf("invalid step count %q", args[0])
+			}
+			n = v
+		}
+		return d.run(func(int) bool { n--; return n == 0 })
+	case "next", "n":
+		return d.next()
+	case "finish", "fin":
+		return d.finish()
+	case "continue", "c":
+		return d.run(func(int) bool { return false })
+	case "break", "b":
+		if len(args) == 0 {
+			d.listBreakpoints()
+			return nil
+		}
+		return d.addBreakpoint(args)
+	case "ignore":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: ignore addr n")
+		}
+		addr, err := d.parseAddr(args[0])
+		if err != nil {
+			return err
+		}
+		bp := d.breakpoints[addr]
+		if bp == nil {
+			return fmt.Errorf("no breakpoint at %#04x", addr)
+		}
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid ignore count %q", args[1])
+		}
+		bp.ignore = bp.hits + n
+	case "delete", "d":
+		if len(args) == 0 {
+			d.breakpoints = map[uint16]*breakpoint{}
+			return nil
+		}
+		addr, err := d.parseAddr(args[0])
+		if err != nil {
+			return err
+		}
+		if d.breakpoints[addr] == nil {
+			return fmt.Errorf("no breakpoint at %#04x", addr)
+		}
+		delete(d.breakpoints, addr)
+	case "watch", "w":
+		if len(args) == 0 {
+			d.listWatchpoints()
+			return nil
+		}
+		return d.addWatchpoint(args)
+	case "unwatch":
+		if len(args) != 1 {
+			return fmt.Errorf("usage: unwatch id")
+		}
+		id, err := strconv.Atoi(args[0])
+		if err != nil || !d.gb.MMU.RemoveWatchpoint(id) {
+			return fmt.Errorf("no watchpoint %q", args[0])
+		}
+	case "backtrace", "bt":
+		fmt.Fprint(d.out, d.gb.FormatBacktrace())
+		if mismatches := d.gb.CPU.StackMismatches(); len(mismatches) > 0 {
+			fmt.Fprintf(d.out, "Last call stack mismatch: %s\n", mismatches[len(mismatches) - 1])
+		}
+	case "regs", "r":
+		fmt.Fprint(d.out, d.gb.CPU.RegisterDump())
+	case "mem", "x":
+		return d.dumpMemory(args)
+	case "disas", "dis":
+		return d.disassemble(args)
+	case "help", "h":
+		fmt.Fprint(d.out, helpText)
+	default:
+		return fmt.Errorf("unknown command %q, try help", cmd)
+	}
+	return nil
+}
+
+// Steps the machine until done returns true (called after every instruction
+// with the opcode that was executed), a breakpoint is hit or emulation stops.
+func (d *Debugger) run(done func(opcode int) bool) error {
+	d.interrupted.Store(false)
+	defer d.printLocation()
+	for first := true; ; first = false {
+		pc := d.gb.CPU.PC
+		// Don't stop on the breakpoint we are resuming from.
+		if bp := d.breakpoints[pc]; !first && bp != nil && bp.shouldBreak(d.gb) {
+			fmt.Fprintf(d.out, "Breakpoint hit at %s (hit %d)\n", d.gb.FormatAddr(pc), bp.hits)
+			return nil
+		}
+		if d.interrupted.Load() {
+			fmt.Fprintln(d.out, "Interrupted")
+			return nil
+		}
+		opcode := d.gb.MMU.Peek(pc)
+		if _, err := d.gb.Step(); err != nil {
+			if errors.Is(err, gameboy.ErrBreak) {
+				fmt.Fprintf(d.out, "Stopped: %v\n", err)
+				return nil
+			}
+			return fmt.Errorf("emulation error: %v", err)
+		}
+		if done(int(opcode)) {
+			return nil
+		}
+	}
+}
+
+// Steps over CALL and RST by running until execution returns right after them.
+func (d *Debugger) next() error {
+	cpu := d.gb.CPU
+	if !gameboy.IsCall(d.gb.MMU.Peek(cpu.PC)) {
+		return d.run(func(int) bool { return true })
+	}
+	_, ret := cpu.DecodeAt(cpu.PC)
+	sp := cpu.SP.Value()
+	return d.run(func(int) bool {
+		return cpu.PC == ret && cpu.SP.Value() >= sp
+	})
+}
+
+// Runs until a return pops the frame of the current subroutine.
+func (d *Debugger) finish() error {
+	cpu := d.gb.CPU
+	sp := cpu.SP.Value()
+	return d.run(func(opcode int) bool {
+		return gameboy.IsReturn(byte(opcode)) && cpu.SP.Value() > sp
+	})
+}
+
+// Parses "addr [if cond]".
+func (d *Debugger) addBreakpoint(args []string) error {
+	addr, err := d.parseAddr(args[0])
+	if err != nil {
+		return err
+	}
+	bp := &breakpoint{}
+	if len(args) > 1 {
+		if args[1] != "if" || len(args) == 2 {
+			return fmt.Errorf("usage: break addr [if cond]")
+		}
+		if bp.cond, err = ParseCondition(strings.Join(args[2:], " "), d.gb.Symbols); err != nil {
+			return err
+		}
+	}
+	d.breakpoints[addr] = bp
+	fmt.Fprintf(d.out, "Breakpoint at %s\n", d.gb.FormatAddr(addr))
+	return nil
+}
+
+func (bp *breakpoint) shouldBreak(gb *gameboy.GB) bool {
+	if bp.cond != nil && !bp.cond.Eval(gb) {
+		return false
+	}
+	bp.hits++
+	return bp.hits > bp.ignore
+}
+
+func (d *Debugger) listBreakpoints() {
+	if len(d.breakpoints) == 0 {
+		fmt.Fprintln(d.out, "No breakpoints")
+		return
+	}
+	addrs := make([]int, 0, len(d.breakpoints))
+	for addr := range d.breakpoints {
+		addrs = append(addrs, int(addr))
+	}
+	sort.Ints(addrs)
+	for _, addr := range addrs {
+		bp := d.breakpoints[uint16(addr)]
+		line := fmt.Sprintf("  %s  hits: %d", d.gb.FormatAddr(uint16(addr)), bp.hits)
+		if bp.cond != nil {
+			line += fmt.Sprintf("  if %s", bp.cond)
+		}
+		if bp.ignore > bp.hits {
+			line += fmt.Sprintf("  (ignoring next %d)", bp.ignore - bp.hits)
+		}
+		fmt.Fprintln(d.out, line)
+	}
+}
+
+var watchKinds = map[string]gameboy.WatchKind{
+	"r":  gameboy.WatchRead,
+	"w":  gameboy.WatchWrite,
+	"rw": gameboy.WatchRead | gameboy.WatchWrite,
+	"c":  gameboy.WatchChange,
+}
+
+// Parses "[kind] start[-end]".
+func (d *Debugger) addWatchpoint(args []string) error {
+	kind := gameboy.WatchWrite
+	if k, ok := watchKinds[args[0]]; ok {
+		kind = k
+		args = args[1:]
+	}
+	if len(args) != 1 {
+		return fmt.Errorf("usage: watch [r|w|rw|c] start[-end]")
+	}
+	startStr, endStr, isRange := strings.Cut(args[0], "-")
+	start, err := d.parseAddr(startStr)
+	if err != nil {
+		return err
+	}
+	end := start
+	if isRange {
+		if end, err = d.parseAddr(endStr); err != nil {
+			return err
+		}
+		if end < start {
+			return fmt.Errorf("watch range %#04x-%#04x is empty", start, end)
+		}
+	}
+	wp := d.gb.MMU.AddWatchpoint(start, end, kind)
+	fmt.Fprintf(d.out, "Watchpoint %d: %s %#04x-%#04x\n", wp.ID, wp.Kind, wp.Start, wp.End)
+	return nil
+}
+
+func (d *Debugger) listWatchpoints() {
+	wps := d.gb.MMU.Watchpoints()
+	if len(wps) == 0 {
+		fmt.Fprintln(d.out, "No watchpoints")
+		return
+	}
+	for _, wp := range wps {
+		fmt.Fprintf(d.out, "  %d: %s %#04x-%#04x\n", wp.ID, wp.Kind, wp.Start, wp.End)
+	}
+}
+
+func (d *Debugger) dumpMemory(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: mem addr [len]")
+	}
+	addr, err := d.parseAddr(args[0])
+	if err != nil {
+		return err
+	}
+	n := 64
+	if len(args) > 1 {
+		if n, err = strconv.Atoi(args[1]); err != nil || n < 1 {
+			return fmt.Errorf("invalid length %q", args[1])
+		}
+	}
+	for row := 0; row < n; row += 16 {
+		line := fmt.Sprintf("%04x:", addr + uint16(row))
+		ascii := ""
+		for col := 0; col < 16 && row + col < n; col++ {
+			b := d.gb.MMU.Peek(addr + uint16(row + col))
+			line += fmt.Sprintf(" %02x", b)
+			if b >= 0x20 && b < 0x7F {
+				ascii += string(rune(b))
+			} else {
+				ascii += "."
+			}
+		}
+		fmt.Fprintf(d.out, "%-54s %s\n", line, ascii)
+	}
+	return nil
+}
+
+func (d *Debugger) disassemble(args []string) error {
+	cpu := d.gb.CPU
+	addr := cpu.PC
+	n := 10
+	var err error
+	if len(args) > 0 {
+		if addr, err = d.parseAddr(args[0]); err != nil {
+			return err
+		}
+	}
+	if len(args) > 1 {
+		if n, err = strconv.Atoi(args[1]); err != nil || n < 1 {
+			return fmt.Errorf("invalid instruction count %q", args[1])
+		}
+	}
+	for i := 0; i < n; i++ {
+		ii, next := cpu.DecodeAt(addr)
+		marker := []byte("  ")
+		if d.breakpoints[addr] != nil {
+			marker[0] = '*'
+		}
+		if addr == cpu.PC {
+			marker[1] = '>'
+		}
+		d.printLabel(addr)
+		fmt.Fprintf(d.out, "%s %s", marker, ii.DebugInfo(cpu))
+		addr = next
+	}
+	return nil
+}
+
+// Shows the instruction about to be executed.
+func (d *Debugger) printLocation() {
+	ii, _ := d.gb.CPU.DecodeAt(d.gb.CPU.PC)
+	d.printLabel(d.gb.CPU.PC)
+	fmt.Fprintf(d.out, "=> %s", ii.DebugInfo(d.gb.CPU))
+}
+
+// Prints a "Label:" line if a symbol is defined at addr.
+func (d *Debugger) printLabel(addr uint16) {
+	if label, ok := d.gb.SymbolAt(addr); ok {
+		fmt.Fprintf(d.out, "%s:\n", label)
+	}
+}
+
+// Parses a hexadecimal address or a label from the symbol file.
+func (d *Debugger) parseAddr(s string) (uint16, error) {
+	if sym, ok := d.gb.Symbols.Lookup(s); ok {
+		return sym.Addr, nil
+	}
+	s = strings.TrimPrefix(strings.TrimPrefix(strings.ToLower(s), "0x"), "$")
+	v, err := strconv.ParseUint(s, 16, 16)
+	if err != nil {
+		return 0, fmt.Errorf("invalid address %q", s)
+	}
+	return uint16(v), nil
+}
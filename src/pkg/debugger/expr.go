@@ -0,0 +1,275 @@
+package debugger
+
+/*
+Condition expressions for breakpoints and watchpoints, e.g.
+
+	A == 0x3C && [0xC0A0] > 4
+
+Operands are numbers (decimal, 0x or $ hexadecimal), registers (A B C D E F
+H L AF BC DE HL SP PC), flags (ZF NF HF CF, 1 when set), labels from the
+symbol file (their address) and memory bytes ([expr]). Operators, from lowest to highest precedence:
+
+	||  &&  == != < <= > >=  |  ^  &  + -  unary ! -
+
+An expression is true when it evaluates to a non-zero value.
+*/
+import (
+	"fmt"
+	"gopherboy/pkg/gameboy"
+	"gopherboy/pkg/symbols"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type expr func(gb *gameboy.GB) int
+
+type Condition struct {
+	src  string
+	eval expr
+}
+
+func (c *Condition) String() string {
+	return c.src
+}
+
+// Eval reports whether the condition holds for the current machine state.
+func (c *Condition) Eval(gb *gameboy.GB) bool {
+	return c.eval(gb) != 0
+}
+
+// ParseCondition parses src, resolving labels with syms, which may be nil.
+func ParseCondition(src string, syms *symbols.Table) (*Condition, error) {
+	p := &exprParser{tokens: tokenize(src), syms: syms}
+	e, err := p.parseBinary(0)
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok != "" {
+		return nil, fmt.Errorf("unexpected %q in condition", tok)
+	}
+	return &Condition{src: src, eval: e}, nil
+}
+
+var registers = map[string]expr{
+	"A":  func(gb *gameboy.GB) int { return int(gb.CPU.AF.Hi()) },
+	"F":  func(gb *gameboy.GB) int { return int(gb.CPU.AF.Lo()) },
+	"B":  func(gb *gameboy.GB) int { return int(gb.CPU.BC.Hi()) },
+	"C":  func(gb *gameboy.GB) int { return int(gb.CPU.BC.Lo()) },
+	"D":  func(gb *gameboy.GB) int { return int(gb.CPU.DE.Hi()) },
+	"E":  func(gb *gameboy.GB) int { return int(gb.CPU.DE.Lo()) },
+	"H":  func(gb *gameboy.GB) int { return int(gb.CPU.HL.Hi()) },
+	"L":  func(gb *gameboy.GB) int { return int(gb.CPU.HL.Lo()) },
+	"AF": func(gb *gameboy.GB) int { return int(gb.CPU.AF.Value()) },
+	"BC": func(gb *gameboy.GB) int { return int(gb.CPU.BC.Value()) },
+	"DE": func(gb *gameboy.GB) int { return int(gb.CPU.DE.Value()) },
+	"HL": func(gb *gameboy.GB) int { return int(gb.CPU.HL.Value()) },
+	"SP": func(gb *gameboy.GB) int { return int(gb.CPU.SP.Value()) },
+	"PC": func(gb *gameboy.GB) int { return int(gb.CPU.PC) },
+	"ZF": flag(gameboy.Z_IDX),
+	"NF": flag(gameboy.N_IDX),
+	"HF": flag(gameboy.H_IDX),
+	"CF": flag(gameboy.C_IDX),
+}
+
+func flag(idx uint8) expr {
+	return func(gb *gameboy.GB) int {
+		return int(gb.CPU.AF.Lo() >> idx & 1)
+	}
+}
+
+func boolToInt(v bool) int {
+	if v {
+		return 1
+	}
+	return 0
+}
+
+// Binary operators by precedence level, lowest first.
+var binaryOps = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!=", "<=", ">=", "<", ">"},
+	{"|"},
+	{"^"},
+	{"&"},
+	{"+", "-"},
+}
+
+func applyBinary(op string, l, r int) int {
+	switch op {
+	case "||":
+		return boolToInt(l != 0 || r != 0)
+	case "&&":
+		return boolToInt(l != 0 && r != 0)
+	case "==":
+		return boolToInt(l == r)
+	case "!=":
+		return boolToInt(l != r)
+	case "<":
+		return boolToInt(l < r)
+	case "<=":
+		return boolToInt(l <= r)
+	case ">":
+		return boolToInt(l > r)
+	case ">=":
+		return boolToInt(l >= r)
+	case "|":
+		return l | r
+	case "^":
+		return l ^ r
+	case "&":
+		return l & r
+	case "+":
+		return l + r
+	}
+	return l - r
+}
+
+func tokenize(src string) []string {
+	var tokens []string
+	for i := 0; i < len(src); {
+		c := rune(src[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case unicode.IsLetter(c) || unicode.IsDigit(c) || c == '$' || c == '_' || c == '.':
+			j := i + 1
+			for j < len(src) && (unicode.IsLetter(rune(src[j])) || unicode.IsDigit(rune(src[j])) || src[j] == '_' || src[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, src[i:j])
+			i = j
+		default:
+			if i + 1 < len(src) {
+				switch two := src[i:i+2]; two {
+				case "||", "&&", "==", "!=", "<=", ">=":
+					tokens = append(tokens, two)
+					i += 2
+					continue
+				}
+			}
+			tokens = append(tokens, string(c))
+			i++
+		}
+	}
+	return tokens
+}
+
+type exprParser struct {
+	tokens []string
+	pos    int
+	syms   *symbols.Table
+}
+
+func (p *exprParser) peek() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *exprParser) next() string {
+	tok := p.peek()
+	p.pos++
+	return tok
+}
+
+func (p *exprParser) parseBinary(level int) (expr, error) {
+	if level == len(binaryOps) {
+		return p.parseUnary()
+	}
+	left, err := p.parseBinary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := p.peek()
+		found := false
+		for _, candidate := range binaryOps[level] {
+			found = found || op == candidate
+		}
+		if !found {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseBinary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(gb *gameboy.GB) int { return applyBinary(op, l(gb), right(gb)) }
+	}
+}
+
+func (p *exprParser) parseUnary() (expr, error) {
+	switch p.peek() {
+	case "!":
+		p.next()
+		e, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return func(gb *gameboy.GB) int { return boolToInt(e(gb) == 0) }, nil
+	case "-":
+		p.next()
+		e, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return func(gb *gameboy.GB) int { return -e(gb) }, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *exprParser) parsePrimary() (expr, error) {
+	tok := p.next()
+	switch {
+	case tok == "":
+		return nil, fmt.Errorf("unexpected end of condition")
+	case tok == "(":
+		e, err := p.parseBinary(0)
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != ")" {
+			return nil, fmt.Errorf("missing ) in condition")
+		}
+		return e, nil
+	case tok == "[":
+		addr, err := p.parseBinary(0)
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != "]" {
+			return nil, fmt.Errorf("missing ] in condition")
+		}
+		return func(gb *gameboy.GB) int { return int(gb.MMU.Peek(uint16(addr(gb)))) }, nil
+	}
+	if reg, ok := registers[strings.ToUpper(tok)]; ok {
+		return reg, nil
+	}
+	if sym, ok := p.syms.Lookup(tok); ok {
+		addr := int(sym.Addr)
+		return func(*gameboy.GB) int { return addr }, nil
+	}
+	v, err := parseNumber(tok)
+	if err != nil {
+		return nil, fmt.Errorf("unknown operand %q in condition", tok)
+	}
+	return func(*gameboy.GB) int { return v }, nil
+}
+
+// Numbers in conditions are decimal unless prefixed with 0x or $, a leading
+// zero doesn't make them octal.
+func parseNumber(s string) (int, error) {
+	base := 10
+	switch {
+	case strings.HasPrefix(s, "$"):
+		s, base = s[1:], 16
+	case strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X"):
+		s, base = s[2:], 16
+	}
+	v, err := strconv.ParseUint(s, base, 32)
+	return int(v), err
+}
@@ -0,0 +1,99 @@
+package debugger
+
+import (
+	"gopherboy/pkg/gameboy"
+	"gopherboy/pkg/symbols"
+	"testing"
+)
+
+// A machine with known registers and a few bytes of WRAM set.
+func exprTestGB() *gameboy.GB {
+	gb := gameboy.NewGB("", "", false)
+	gb.CPU.AF.Set(0x3CB0)
+	gb.CPU.BC.Set(0x0102)
+	gb.CPU.DE.Set(0x0304)
+	gb.CPU.HL.Set(0xC0A0)
+	gb.CPU.SP.Set(0xDFF0)
+	gb.CPU.PC = 0x0150
+	gb.MMU.Poke(0xC0A0, 5)
+	gb.MMU.Poke(0xC0A1, 0x80)
+	return gb
+}
+
+func TestConditionEval(t *testing.T) {
+	syms := symbols.NewTable()
+	syms.Add(symbols.Symbol{Name: "wCounter", Bank: 0, Addr: 0xC0A0})
+	syms.Add(symbols.Symbol{Name: "Main.loop", Bank: 0, Addr: 0x0150})
+	gb := exprTestGB()
+	tests := []struct {
+		src  string
+		want bool
+	}{
+		{"A == 0x3C", true},
+		{"a == $3c", true},
+		{"A == 60", true},
+		// Decimal, not octal.
+		{"010 == 10", true},
+		{"010 == 8", false},
+		{"0X10 == 16", true},
+		{"F == $B0", true},
+		{"ZF && HF && CF && !NF", true},
+		{"NF", false},
+		{"BC == $0102 && B == 1 && C == 2", true},
+		{"DE == $0304 && D == 3 && E == 4", true},
+		{"HL == $C0A0 && H == $C0 && L == $A0", true},
+		{"AF == $3CB0", true},
+		{"SP == $DFF0", true},
+		{"PC == Main.loop", true},
+		{"[0xC0A0] > 4", true},
+		{"[HL] == 5", true},
+		{"[HL + 1] == $80", true},
+		{"[wCounter] == 5", true},
+		{"A == 0x3C && [0xC0A0] > 4", true},
+		{"A == 0 || [wCounter] == 5", true},
+		{"A == 0 || B == 0", false},
+		{"1 + 2 == 3", true},
+		{"-1 + 2 == 1", true},
+		{"1 | 2 == 3", true},
+		{"(1 | 2) == 3", true},
+		{"6 & 3 == 2", true},
+		{"6 ^ 3 == 5", true},
+		{"2 - 3 < 0", true},
+		{"3 <= 3 && 3 >= 3 && 2 != 3", true},
+		{"!0", true},
+		{"!(A == $3C)", false},
+	}
+	for _, test := range tests {
+		cond, err := ParseCondition(test.src, syms)
+		if err != nil {
+			t.Errorf("ParseCondition(%q): %v", test.src, err)
+			continue
+		}
+		if got := cond.Eval(gb); got != test.want {
+			t.Errorf("%q = %v, want %v", test.src, got, test.want)
+		}
+		if cond.String() != test.src {
+			t.Errorf("String() = %q, want %q", cond.String(), test.src)
+		}
+	}
+}
+
+func TestParseConditionErrors(t *testing.T) {
+	for _, src := range []string{
+		"",
+		"A ==",
+		"(A == 1",
+		"[HL",
+		"A == 1 )",
+		"A B",
+		"wUnknown == 1",
+		"08x == 1",
+		"$ == 1",
+		"0x == 1",
+		"$100000000 == 1",
+	} {
+		if _, err := ParseCondition(src, nil); err == nil {
+			t.Errorf("ParseCondition(%q) succeeded, want an error", src)
+		}
+	}
+}
@@ -1,65 +1,191 @@
-package main
+// Disassembles SM83 machine code into RGBDS assembly.
+//
+// Code is found by following the control flow from the entry points, see
+// Options. Everything else is emitted as data.
+//
+// ROMs are split in 16 KiB banks: bank 0 is mapped at 0x0000-0x3FFF, the
+// others at 0x4000-0x7FFF, so addresses are given as bank:address. Jumps and
+// calls from bank 0 into 0x4000-0x7FFF reach whichever bank the memory bank
+// controller maps there. It is known when the code selects it by writing an
+// immediate to 0x2000-0x3FFF, or when the ROM has a single switchable bank.
+//
+// The output of Listing.WriteTo reassembles to the original bytes with
+// rgbasm and rgblink: data and bytes which don't decode to an instruction are
+// emitted with db, and jumps, calls and relative jumps whose target is part
+// of the listing point at a label. Each bank goes in its own section.
+package disassembler
 
 import (
-	"flag"
 	"fmt"
-	"gopherboy/common"
-	"os"
+	"gopherboy/pkg/common"
+	"gopherboy/pkg/symbols"
+	"strings"
 )
 
-var file string
-var outDir string
+type Options struct {
+	// Address the first byte is mapped at, e.g. 0 for boot ROMs and
+	// cartridges, 0x4000 for a single switchable bank.
+	Origin uint16
+	// Labels for addresses, nil to only name jump targets after their
+	// address.
+	Symbols *symbols.Table
+	// Decode every byte as code from the start instead of following the
+	// control flow.
+	Linear bool
+	// Code to follow besides EntryPoints, e.g. functions only called
+	// through pointers.
+	Entries []Location
+	// Jump tables, whose entries are followed as well.
+	JumpTables []JumpTable
+}
+
+// Location is a ROM address along with the bank it is in, 0 for
+// 0x0000-0x3FFF.
+type Location struct {
+	Bank uint16
+	Addr uint16
+}
 
+func (loc Location) String() string {
+	return fmt.Sprintf("%02x:%04x", loc.Bank, loc.Addr)
+}
 
-var instrLen = [0x100]int{1, 3, 1, 1, 1, 1, 2, 1, 3, 1, 1, 1, 1, 1, 2, 1, 2, 3, 1, 1, 1, 1, 2, 1, 2, 1, 1, 1, 1, 1, 2, 1, 2, 3, 1, 1, 1, 1, 2, 1, 2, 1, 1, 1, 1, 1, 2, 1, 2, 3, 1, 1, 1, 1, 2, 1, 2, 1, 1, 1, 1, 1, 2, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 3, 3, 3, 1, 2, 1, 1, 1, 3, 1, 3, 3, 2, 1, 1, 1, 3, 1, 3, 1, 2, 1, 1, 1, 3, 1, 3, 1, 2, 1, 2, 1, 1, 1, 1, 1, 2, 1, 2, 1, 3, 1, 1, 1, 2, 1, 2, 1, 1, 1, 1, 1, 2, 1, 2, 1, 3, 1, 1, 1, 2, 1}
+// JumpTable is a table of little endian code addresses, which the control
+// flow doesn't reveal: the code reaching them jumps with JP HL. Entries
+// pointing at 0x4000-0x7FFF are in the same bank as the table.
+type JumpTable struct {
+	Location
+	// Number of entries.
+	Len int
+}
 
-type InstrInfo struct {
-	opcode byte
-	addr uint16
-	instr string
+// EntryPoints are the addresses execution starts from: the cartridge entry
+// point, the RST vectors and the interrupt vectors.
+var EntryPoints = []uint16{
+	0x0100,
+	0x00, 0x08, 0x10, 0x18, 0x20, 0x28, 0x30, 0x38,
+	0x40, 0x48, 0x50, 0x58, 0x60,
 }
 
-func (ii InstrInfo) String() string {
-	return fmt.Sprintf("%#4x %#2x\t%s\n", ii.addr, ii.opcode, ii.instr)
+// Instruction is a decoded instruction, or data: a run of bytes which aren't
+// code or a jump table entry.
+type Instruction struct {
+	// Position in the ROM.
+	Offset int
+	Bank   uint16
+	Addr   uint16
+	// The instruction bytes, prefix and operands included.
+	Bytes []byte
+	// Nil for data: illegal opcodes, and instructions cut short by the end
+	// of the ROM.
+	Op *common.Opcode
+	// Value of the immediate operand, 0 when there is none.
+	Imm uint16
+	// Absolute target of jumps, calls, RSTs and jump table entries, valid
+	// when HasTarget.
+	Target    uint16
+	HasTarget bool
+	// Bank of Target, set by Disassemble. 0 when Target is in
+	// 0x4000-0x7FFF and the bank mapped there is unknown.
+	TargetBank uint16
+	// A jump table entry rather than an instruction.
+	Pointer bool
 }
 
-func disassemble(bytecode []byte) []InstrInfo {
-	i := 0
-	var out []InstrInfo
-	for i < len(bytecode) {
-		opcode := bytecode[i]
-		len := instrLen[opcode]
-		instrLookup := common.InstrDebugLookup
-		if opcode == 0xCB {
-			opcode = bytecode[i + 1]
-			len = 2
-			instrLookup = common.PrefixInstrDebugLookup
-		}
-		instrInfo := InstrInfo{
-			opcode: opcode,
-			addr: uint16(i),
-			instr: instrLookup[opcode],
+// Decode decodes the instruction at offset, which is mapped at addr. Bank and
+// TargetBank are left to the caller.
+func Decode(rom []byte, offset int, addr uint16) Instruction {
+	in := Instruction{Offset: offset, Addr: addr, Bytes: rom[offset:offset + 1]}
+	op := &common.Opcodes[rom[offset]]
+	if rom[offset] == 0xCB {
+		if offset + 1 >= len(rom) {
+			return in
 		}
-		out = append(out, instrInfo)
-		i += len
+		op = &common.CBOpcodes[rom[offset + 1]]
+	}
+	if strings.HasPrefix(op.Mnemonic, "ILLEGAL") || offset + op.Bytes > len(rom) {
+		return in
+	}
+	in.Op = op
+	in.Bytes = rom[offset:offset + op.Bytes]
+	if rom[offset] == 0xCB {
+		return in
+	}
+	switch op.Bytes {
+	case 2:
+		in.Imm = uint16(in.Bytes[1])
+	case 3:
+		in.Imm = uint16(in.Bytes[2]) << 8 | uint16(in.Bytes[1])
+	}
+	switch op.Mnemonic {
+	case "JP", "CALL":
+		// JP HL has no immediate target.
+		in.HasTarget = op.Bytes == 3
+		in.Target = in.Imm
+	case "JR":
+		in.HasTarget = true
+		in.Target = addr + 2 + uint16(int8(in.Imm))
+	case "RST":
+		in.HasTarget = true
+		in.Target = uint16(rom[offset] & 0x38)
+	}
+	return in
+}
+
+// Disassemble decodes the code of rom, see Options.
+func Disassemble(rom []byte, opts Options) *Listing {
+	f := newFlow(rom, opts)
+	if opts.Linear {
+		f.sweep()
+	} else {
+		f.run()
+	}
+	l := &Listing{
+		Instructions: f.listing(),
+		opts:         opts,
+		labels:       map[Location]string{},
+		singleBank:   f.singleBank(),
 	}
-	return out
+	l.nameLabels()
+	return l
 }
 
+// Label returns the name of the label at bank:addr, if there is one.
+func (l *Listing) Label(bank, addr uint16) (string, bool) {
+	name, ok := l.labels[Location{bank, addr}]
+	return name, ok
+}
 
-func main() {
-	flag.StringVar(&file, "file", "../../roms/dmg_boot.bin", "The path for the program to disassemble.")
-	flag.StringVar(&outDir, "out_dir", "./", "The path for the program to disassemble.")
-	flag.Parse()
-	
-	bytecode, err := os.ReadFile(file)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "could not read the code file '%s' got: %v", file, err)
-		os.Exit(1)
+// Names the addresses of the listing which are jumped to, given as hints, or
+// have a symbol.
+// Only line starts can be labelled, other targets are left as numbers.
+func (l *Listing) nameLabels() {
+	starts := map[Location]bool{}
+	for _, in := range l.Instructions {
+		loc := Location{in.Bank, in.Addr}
+		starts[loc] = true
+		if name, ok := l.opts.Symbols.Name(in.Bank, in.Addr); ok {
+			l.labels[loc] = name
+		}
 	}
-	fmt.Printf("Disassembling: %v\n", file)
-	src := disassemble(bytecode)
-	for _,v := range src {
-		fmt.Print(v.String())
+	var targets []Location
+	for _, in := range l.Instructions {
+		if in.HasTarget {
+			targets = append(targets, Location{in.TargetBank, in.Target})
+		}
 	}
-}
\ No newline at end of file
+	// The hints are only reached through pointers, which are more readable
+	// as labels too.
+	targets = append(targets, l.opts.Entries...)
+	for _, t := range l.opts.JumpTables {
+		targets = append(targets, t.Location)
+	}
+	for _, loc := range targets {
+		if !starts[loc] {
+			continue
+		}
+		if _, ok := l.labels[loc]; !ok {
+			l.labels[loc] = generatedLabel(loc)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package disassembler
+
+import "testing"
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		code      []byte
+		addr      uint16
+		mnemonic  string
+		size      int
+		imm       uint16
+		target    uint16
+		hasTarget bool
+	}{
+		{[]byte{0x00}, 0x0150, "NOP", 1, 0, 0, false},
+		{[]byte{0x3E, 0x12}, 0x0150, "LD", 2, 0x12, 0, false},
+		{[]byte{0x01, 0x34, 0x12}, 0x0150, "LD", 3, 0x1234, 0, false},
+		{[]byte{0xE0, 0x44}, 0x0150, "LDH", 2, 0x44, 0, false},
+		// JR targets are relative to the next instruction.
+		{[]byte{0x18, 0xFE}, 0x0150, "JR", 2, 0xFE, 0x0150, true},
+		{[]byte{0x20, 0x10}, 0x0150, "JR", 2, 0x10, 0x0162, true},
+		{[]byte{0x38, 0x80}, 0x0150, "JR", 2, 0x80, 0x00D2, true},
+		{[]byte{0x18, 0xFC}, 0x0000, "JR", 2, 0xFC, 0xFFFE, true},
+		{[]byte{0xC3, 0x00, 0x40}, 0x0150, "JP", 3, 0x4000, 0x4000, true},
+		{[]byte{0xCD, 0x67, 0x45}, 0x0150, "CALL", 3, 0x4567, 0x4567, true},
+		{[]byte{0xE9}, 0x0150, "JP", 1, 0, 0, false},
+		{[]byte{0xC7}, 0x0150, "RST", 1, 0, 0x00, true},
+		{[]byte{0xFF}, 0x0150, "RST", 1, 0, 0x38, true},
+		{[]byte{0xCB, 0x11}, 0x0150, "RL", 2, 0, 0, false},
+		{[]byte{0xCB, 0x7C}, 0x0150, "BIT", 2, 0, 0, false},
+	}
+	for _, test := range tests {
+		in := Decode(test.code, 0, test.addr)
+		if in.Op == nil {
+			t.Errorf("% x: not decoded", test.code)
+			continue
+		}
+		if in.Op.Mnemonic != test.mnemonic || len(in.Bytes) != test.size || in.Imm != test.imm ||
+			in.HasTarget != test.hasTarget || in.Target != test.target {
+			t.Errorf("% x at %#04x: got %s, %d bytes, imm %#04x, target %#04x %v; want %s, %d bytes, imm %#04x, target %#04x %v",
+				test.code, test.addr, in.Op.Mnemonic, len(in.Bytes), in.Imm, in.Target, in.HasTarget,
+				test.mnemonic, test.size, test.imm, test.target, test.hasTarget)
+		}
+	}
+}
+
+func TestDecodeData(t *testing.T) {
+	for _, code := range [][]byte{
+		// Illegal opcode.
+		{0xD3},
+		// Operands cut short by the end of the ROM.
+		{0x01, 0x34},
+		{0xCD},
+		{0xCB},
+	} {
+		in := Decode(code, 0, 0x0150)
+		if in.Op != nil || len(in.Bytes) != 1 {
+			t.Errorf("% x: got %d bytes of %v, want a data byte", code, len(in.Bytes), in.Op)
+		}
+	}
+	// Decoding stops at the end of the slice, not at the end of the
+	// instruction's operands.
+	in := Decode([]byte{0x00, 0x01, 0x34, 0x12}, 1, 0x0151)
+	if in.Op == nil || in.Imm != 0x1234 || in.Offset != 1 || in.Addr != 0x0151 {
+		t.Errorf("LD BC,$1234 at offset 1: got %+v", in)
+	}
+}
+
+// RGBDS spells some instructions differently from Opcodes.json.
+func TestText(t *testing.T) {
+	tests := []struct {
+		code []byte
+		want string
+	}{
+		{[]byte{0x00}, "nop"},
+		{[]byte{0x3E, 0x12}, "ld a, $12"},
+		{[]byte{0x01, 0x34, 0x12}, "ld bc, $1234"},
+		{[]byte{0xE2}, "ldh [c], a"},
+		{[]byte{0xF2}, "ldh a, [c]"},
+		{[]byte{0xE0, 0x44}, "ldh [$ff44], a"},
+		{[]byte{0xF8, 0x05}, "ld hl, sp+5"},
+		{[]byte{0xF8, 0xFB}, "ld hl, sp-5"},
+		{[]byte{0xE8, 0xFE}, "add sp, -2"},
+		{[]byte{0x10, 0x00}, "stop"},
+		// rgbasm can't produce STOP with another byte.
+		{[]byte{0x10, 0x01}, "db $10, $01"},
+		{[]byte{0x22}, "ld [hl+], a"},
+		{[]byte{0x3A}, "ld a, [hl-]"},
+		{[]byte{0x36, 0x07}, "ld [hl], $07"},
+		{[]byte{0xEA, 0x00, 0xC0}, "ld [$c000], a"},
+		{[]byte{0xFF}, "rst $38"},
+		{[]byte{0xE9}, "jp hl"},
+		{[]byte{0xCB, 0x7C}, "bit 7, h"},
+		{[]byte{0xCB, 0x36}, "swap [hl]"},
+		// Targets outside the listing stay numbers, JR relative to itself.
+		{[]byte{0xCD, 0x67, 0x45}, "call $4567"},
+		{[]byte{0x20, 0x10}, "jr nz, @+18"},
+		{[]byte{0x18, 0xFE}, "jr @+0"},
+		{[]byte{0xD3}, "db $d3"},
+	}
+	l := &Listing{}
+	for _, test := range tests {
+		in := Decode(test.code, 0, 0x0150)
+		if got := l.Text(&in); got != test.want {
+			t.Errorf("% x: got %q, want %q", test.code, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,363 @@
+package disassembler
+
+// What each ROM byte was found to be.
+type byteKind uint8
+
+const (
+	unknown byteKind = iota
+	// First byte of an instruction.
+	codeStart
+	// Following bytes of an instruction.
+	code
+	// Jump table entry, the low byte.
+	pointerStart
+	pointer
+)
+
+// Longest run of data bytes on a line.
+const dataLineLen = 8
+
+const bankSize = 0x4000
+
+// Code left to decode.
+type pending struct {
+	Location
+	// Bank mapped at 0x4000-0x7FFF when reaching it, 0 when unknown.
+	romx uint16
+}
+
+// Control flow analysis of a ROM.
+type flow struct {
+	rom  []byte
+	opts Options
+	kind []byteKind
+	// The instructions and jump table entries found, by offset.
+	lines map[int]Instruction
+	queue []pending
+	// Offsets some line has to start at, so they can be labelled.
+	boundaries map[int]bool
+	// The cartridge header asks for an MBC5, which selects banks a bit
+	// differently.
+	mbc5 bool
+}
+
+// Cartridge type byte in the header.
+const cartridgeTypeAddr = 0x0147
+
+func newFlow(rom []byte, opts Options) *flow {
+	f := &flow{
+		rom:        rom,
+		opts:       opts,
+		kind:       make([]byteKind, len(rom)),
+		lines:      map[int]Instruction{},
+		boundaries: map[int]bool{},
+	}
+	if t := f.offset(Location{0, cartridgeTypeAddr}); t >= 0 {
+		f.mbc5 = rom[t] >= 0x19 && rom[t] <= 0x1E
+	}
+	return f
+}
+
+// Where the byte at offset is mapped.
+func (f *flow) location(offset int) Location {
+	a := int(f.opts.Origin) + offset
+	if a < bankSize {
+		return Location{0, uint16(a)}
+	}
+	return Location{uint16(a / bankSize), uint16(bankSize + a % bankSize)}
+}
+
+// ROM offset of loc, or -1 when it isn't part of the ROM.
+func (f *flow) offset(loc Location) int {
+	var a int
+	switch {
+	case loc.Bank == 0 && loc.Addr < bankSize:
+		a = int(loc.Addr)
+	case loc.Bank > 0 && loc.Addr >= bankSize && loc.Addr < 2 * bankSize:
+		a = int(loc.Bank) * bankSize + int(loc.Addr) - bankSize
+	default:
+		return -1
+	}
+	offset := a - int(f.opts.Origin)
+	if offset < 0 || offset >= len(f.rom) {
+		return -1
+	}
+	return offset
+}
+
+// End offset of the bank holding offset. Nothing spans two banks, as they go
+// in different sections.
+func (f *flow) bankEnd(offset int) int {
+	a := int(f.opts.Origin) + offset
+	end := (a / bankSize + 1) * bankSize - int(f.opts.Origin)
+	return min(end, len(f.rom))
+}
+
+// Whether the ROM has no bank besides 1 to map at 0x4000-0x7FFF.
+func (f *flow) singleBank() bool {
+	return int(f.opts.Origin) + len(f.rom) <= 2 * bankSize
+}
+
+func (f *flow) decode(offset int) Instruction {
+	loc := f.location(offset)
+	in := Decode(f.rom[:f.bankEnd(offset)], offset, loc.Addr)
+	in.Bank = loc.Bank
+	return in
+}
+
+// Bank an access to addr reaches while romx is mapped at 0x4000-0x7FFF.
+func (f *flow) resolve(addr, romx uint16) (uint16, bool) {
+	switch {
+	case addr < bankSize:
+		return 0, true
+	case addr < 2 * bankSize:
+		if romx != 0 {
+			return romx, true
+		}
+		if f.singleBank() {
+			return 1, true
+		}
+	}
+	return 0, false
+}
+
+// Bank mapped at 0x4000-0x7FFF while running code of bank: its own, or romx
+// for bank 0.
+func mapped(bank, romx uint16) uint16 {
+	if bank != 0 {
+		return bank
+	}
+	return romx
+}
+
+// Records the target of in, which runs while romx is mapped, and queues it
+// when it is code.
+func (f *flow) follow(in *Instruction, romx uint16) {
+	bank, ok := f.resolve(in.Target, romx)
+	if !ok {
+		return
+	}
+	in.TargetBank = bank
+	target := Location{bank, in.Target}
+	if t := f.offset(target); t >= 0 {
+		f.boundaries[t] = true
+		f.queue = append(f.queue, pending{target, mapped(bank, romx)})
+	}
+}
+
+// Decodes everything reachable from the entry points and the hints.
+func (f *flow) run() {
+	for _, t := range f.opts.JumpTables {
+		f.markTable(t)
+	}
+	for _, addr := range EntryPoints {
+		f.queue = append(f.queue, pending{Location{0, addr}, 0})
+	}
+	for _, loc := range f.opts.Entries {
+		f.queue = append(f.queue, pending{loc, mapped(loc.Bank, 0)})
+	}
+	for len(f.queue) > 0 {
+		p := f.queue[len(f.queue) - 1]
+		f.queue = f.queue[:len(f.queue) - 1]
+		f.trace(p)
+	}
+}
+
+// Decodes every byte from the start as code.
+func (f *flow) sweep() {
+	for offset := 0; offset < len(f.rom); {
+		in := f.decode(offset)
+		if in.Op == nil {
+			offset++
+			continue
+		}
+		if in.HasTarget {
+			bank, _ := f.resolve(in.Target, mapped(in.Bank, 0))
+			in.TargetBank = bank
+		}
+		f.mark(&in, codeStart, code)
+		offset += len(in.Bytes)
+	}
+}
+
+func (f *flow) mark(in *Instruction, start, rest byteKind) {
+	f.kind[in.Offset] = start
+	for i := 1; i < len(in.Bytes); i++ {
+		f.kind[in.Offset + i] = rest
+	}
+	f.lines[in.Offset] = *in
+}
+
+func (f *flow) markTable(t JumpTable) {
+	start := f.offset(t.Location)
+	if start < 0 {
+		return
+	}
+	f.boundaries[start] = true
+	for i := 0; i < t.Len; i++ {
+		offset := start + 2 * i
+		if offset + 1 >= f.bankEnd(start) {
+			return
+		}
+		in := Instruction{
+			Offset:    offset,
+			Bank:      t.Bank,
+			Addr:      t.Addr + uint16(2 * i),
+			Bytes:     f.rom[offset:offset + 2],
+			Target:    uint16(f.rom[offset + 1]) << 8 | uint16(f.rom[offset]),
+			HasTarget: true,
+			Pointer:   true,
+		}
+		f.follow(&in, mapped(t.Bank, 0))
+		f.mark(&in, pointerStart, pointer)
+	}
+}
+
+// Decodes instructions from p until the flow stops falling through,
+// queueing the targets of jumps and calls.
+func (f *flow) trace(p pending) {
+	loc, romx := p.Location, p.romx
+	// Value of A when known, to spot bank switches.
+	a := -1
+	for {
+		offset := f.offset(loc)
+		if offset < 0 || f.kind[offset] != unknown {
+			// Outside the ROM, already decoded, or in the middle of
+			// something else.
+			return
+		}
+		in := f.decode(offset)
+		if in.Op == nil {
+			return
+		}
+		for i := range in.Bytes {
+			if f.kind[offset + i] != unknown {
+				return
+			}
+		}
+		if in.HasTarget {
+			f.follow(&in, romx)
+		}
+		f.mark(&in, codeStart, code)
+		romx, a = f.track(&in, romx, a)
+		if !fallsThrough(&in) {
+			return
+		}
+		loc.Addr += uint16(len(in.Bytes))
+	}
+}
+
+// Follows the bank mapped at 0x4000-0x7FFF and the value of A, -1 when
+// unknown, through in. Banks are selected by writing to 0x2000-0x3FFF.
+func (f *flow) track(in *Instruction, romx uint16, a int) (uint16, int) {
+	op := in.Op
+	switch {
+	case in.Bytes[0] == 0x3E:
+		// LD A,n8
+		return romx, int(in.Imm)
+	case in.Bytes[0] == 0xAF:
+		// XOR A,A
+		return romx, 0
+	case in.Bytes[0] == 0xEA:
+		// LD [a16],A
+		if a >= 0 && in.Imm >= 0x2000 && in.Imm < 0x4000 {
+			romx = f.selectBank(in.Imm, uint16(a), romx)
+		}
+		return romx, a
+	case op.Mnemonic == "CALL" || op.Mnemonic == "RST":
+		return romx, -1
+	case op.Mnemonic == "RLCA" || op.Mnemonic == "RRCA" || op.Mnemonic == "RLA" ||
+		op.Mnemonic == "RRA" || op.Mnemonic == "CPL" || op.Mnemonic == "DAA":
+		return romx, -1
+	}
+	if len(op.Operands) > 0 && !op.Operands[0].Immediate {
+		// Stores A, or writes memory.
+		return romx, a
+	}
+	for _, o := range op.Operands {
+		if o.Name == "A" || o.Name == "AF" {
+			return romx, -1
+		}
+	}
+	return romx, a
+}
+
+// Bank mapped at 0x4000-0x7FFF once val is written to addr, while romx was.
+// MBC1 and MBC3 take the bank number anywhere in 0x2000-0x3FFF, and map bank
+// 1 when asked for 0. MBC5 takes the low 8 bits at 0x2000-0x2FFF and bit 8 at
+// 0x3000-0x3FFF, which ROMs of up to 256 banks keep at 0.
+func (f *flow) selectBank(addr, val, romx uint16) uint16 {
+	if f.mbc5 {
+		if addr >= 0x3000 {
+			if val != 0 {
+				return 0
+			}
+			return romx
+		}
+		if val == 0 {
+			// Bank 0 mapped at 0x4000-0x7FFF isn't followed.
+			return 0
+		}
+	} else if val == 0 {
+		val = 1
+	}
+	if f.offset(Location{val, bankSize}) < 0 {
+		return romx
+	}
+	return val
+}
+
+// Whether execution may continue with the next instruction.
+func fallsThrough(in *Instruction) bool {
+	conditional := len(in.Op.Operands) > 0 && isCondition(in.Op.Operands[0].Name)
+	switch in.Op.Mnemonic {
+	case "JP", "JR", "RET":
+		return conditional
+	case "RETI":
+		return false
+	}
+	return true
+}
+
+func isCondition(name string) bool {
+	return name == "NZ" || name == "Z" || name == "NC" || name == "C"
+}
+
+// Builds the lines of the listing: the decoded instructions, jump table
+// entries and data in between.
+func (f *flow) listing() []Instruction {
+	for _, sym := range f.opts.Symbols.Symbols() {
+		if offset := f.offset(Location{sym.Bank, sym.Addr}); offset >= 0 {
+			f.boundaries[offset] = true
+		}
+	}
+	var out []Instruction
+	for offset := 0; offset < len(f.rom); {
+		in, ok := f.lines[offset]
+		if !ok {
+			loc := f.location(offset)
+			in = Instruction{Offset: offset, Bank: loc.Bank, Addr: loc.Addr, Bytes: f.data(offset)}
+		}
+		out = append(out, in)
+		offset += len(in.Bytes)
+	}
+	return out
+}
+
+// The data bytes of the line starting at offset.
+func (f *flow) data(offset int) []byte {
+	end := offset + 1
+	limit := min(f.bankEnd(offset), offset + dataLineLen)
+	for end < limit && !f.boundaries[end] && f.isData(end) {
+		end++
+	}
+	return f.rom[offset:end]
+}
+
+// Whether the byte at offset is data. Bytes which are part of instructions
+// or table entries but don't start them can be reached when they overlap
+// something else, and are data as well.
+func (f *flow) isData(offset int) bool {
+	k := f.kind[offset]
+	return k != codeStart && k != pointerStart
+}
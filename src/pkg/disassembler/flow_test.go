@@ -0,0 +1,280 @@
+package disassembler
+
+import (
+	"strings"
+	"testing"
+)
+
+// A 32 KiB ROM with code at bank 0 addresses.
+func flowTestROM(code map[uint16][]byte) []byte {
+	rom := make([]byte, 2 * bankSize)
+	// Header and filler, which would decode as instructions.
+	for i := 0x0104; i < 0x0150; i++ {
+		rom[i] = byte(0xCE + i)
+	}
+	for _, addr := range EntryPoints {
+		// RETI
+		rom[addr] = 0xD9
+	}
+	for addr, b := range code {
+		copy(rom[addr:], b)
+	}
+	return rom
+}
+
+var flowTestCode = map[uint16][]byte{
+	// NOP / JP $0150
+	0x0100: {0x00, 0xC3, 0x50, 0x01},
+	// CALL $0160 / JR @+0
+	0x0150: {0xCD, 0x60, 0x01, 0x18, 0xFE},
+	// LD HL,$0200 / JP HL
+	0x0160: {0x21, 0x00, 0x02, 0xE9},
+	// Jump table
+	0x0200: {0x10, 0x02, 0x20, 0x02},
+	// RET
+	0x0210: {0xC9},
+	0x0220: {0xC9},
+	// LD A,1 / RET, never reached.
+	0x0230: {0x3E, 0x01, 0xC9},
+}
+
+// Lines of the listing by bank:address.
+func lines(l *Listing) map[Location]Instruction {
+	m := map[Location]Instruction{}
+	for _, in := range l.Instructions {
+		m[Location{in.Bank, in.Addr}] = in
+	}
+	return m
+}
+
+func TestFlowSeparatesData(t *testing.T) {
+	rom := flowTestROM(flowTestCode)
+	l := Disassemble(rom, Options{})
+	byLoc := lines(l)
+
+	// Every byte is listed exactly once.
+	size := 0
+	for _, in := range l.Instructions {
+		if in.Offset != size {
+			t.Fatalf("line at offset %#x, want %#x", in.Offset, size)
+		}
+		size += len(in.Bytes)
+		if in.Op == nil && len(in.Bytes) > dataLineLen {
+			t.Errorf("%d bytes of data on a line at %#04x", len(in.Bytes), in.Addr)
+		}
+	}
+	if size != len(rom) {
+		t.Errorf("listing covers %d bytes, want %d", size, len(rom))
+	}
+
+	// The header is data.
+	for _, in := range l.Instructions {
+		if in.Addr >= 0x0104 && in.Addr < 0x0150 && in.Op != nil {
+			t.Errorf("header byte at %#04x decoded as %s", in.Addr, in.Op.Mnemonic)
+		}
+	}
+	for _, addr := range []uint16{0x0100, 0x0101, 0x0150, 0x0153, 0x0160, 0x0163} {
+		if in, ok := byLoc[Location{0, addr}]; !ok || in.Op == nil {
+			t.Errorf("%#04x: no instruction", addr)
+		}
+	}
+	// Reached through JP HL only, without hints they stay data.
+	for _, addr := range []uint16{0x0200, 0x0210, 0x0220, 0x0230} {
+		if in, ok := byLoc[Location{0, addr}]; ok && in.Op != nil {
+			t.Errorf("%#04x decoded as %s, want data", addr, in.Op.Mnemonic)
+		}
+	}
+	if got := l.Text(ptr(byLoc[Location{0, 0x0150}])); got != "call L_0160" {
+		t.Errorf("0x0150: got %q, want call L_0160", got)
+	}
+	if got := l.Text(ptr(byLoc[Location{0, 0x0153}])); got != "jr L_0153" {
+		t.Errorf("0x0153: got %q, want jr L_0153", got)
+	}
+}
+
+func ptr(in Instruction) *Instruction {
+	return &in
+}
+
+func TestFlowJumpTable(t *testing.T) {
+	rom := flowTestROM(flowTestCode)
+	l := Disassemble(rom, Options{
+		JumpTables: []JumpTable{{Location{0, 0x0200}, 2}},
+	})
+	byLoc := lines(l)
+	for i, want := range []string{"dw L_0210", "dw L_0220"} {
+		loc := Location{0, 0x0200 + uint16(2 * i)}
+		in, ok := byLoc[loc]
+		if !ok || !in.Pointer {
+			t.Errorf("%v: not a jump table entry", loc)
+			continue
+		}
+		if got := l.Text(&in); got != want {
+			t.Errorf("%v: got %q, want %q", loc, got, want)
+		}
+	}
+	for _, addr := range []uint16{0x0210, 0x0220} {
+		if in := byLoc[Location{0, addr}]; in.Op == nil || in.Op.Mnemonic != "RET" {
+			t.Errorf("%#04x: want the RET reached through the table", addr)
+		}
+	}
+	if label, ok := l.Label(0, 0x0200); !ok || label != "L_0200" {
+		t.Errorf("table label = %q, %v, want L_0200", label, ok)
+	}
+	// Still unreachable.
+	if in := byLoc[Location{0, 0x0230}]; in.Op != nil {
+		t.Errorf("0x0230 decoded as %s, want data", in.Op.Mnemonic)
+	}
+
+	var out strings.Builder
+	if _, err := l.WriteTo(&out); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"SECTION \"ROM Bank $00\", ROM0[$0000]",
+		"L_0200:\n\tdw L_0210",
+		"L_0210:\n\tret",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("listing doesn't contain %q", want)
+		}
+	}
+	if strings.Contains(out.String(), "ld a, $01") {
+		t.Errorf("unreached code at 0x0230 listed as code")
+	}
+}
+
+func TestFlowEntryHint(t *testing.T) {
+	rom := flowTestROM(flowTestCode)
+	l := Disassemble(rom, Options{Entries: []Location{{0, 0x0230}}})
+	in := lines(l)[Location{0, 0x0230}]
+	if got := l.Text(&in); got != "ld a, $01" {
+		t.Errorf("0x0230: got %q, want ld a, $01", got)
+	}
+	if label, ok := l.Label(0, 0x0230); !ok || label != "L_0230" {
+		t.Errorf("entry label = %q, %v, want L_0230", label, ok)
+	}
+}
+
+// A ROM of 4 banks with the given cartridge type, code at bank 0 addresses
+// and a RET at 0x4000 and 0x4010 of banks 2 and 3.
+func bankTestROM(cartType byte, code ...byte) []byte {
+	rom := make([]byte, 4 * bankSize)
+	rom[cartridgeTypeAddr] = cartType
+	copy(rom[0x0100:], code)
+	for bank := 2; bank < 4; bank++ {
+		rom[bank * bankSize] = 0xC9
+		rom[bank * bankSize + 0x10] = 0xC9
+	}
+	return rom
+}
+
+// Text of the CALLs of bank 0, in order.
+func calls(l *Listing) []string {
+	var out []string
+	for i := range l.Instructions {
+		in := &l.Instructions[i]
+		if in.Bank == 0 && in.Op != nil && in.Op.Mnemonic == "CALL" {
+			out = append(out, l.Text(in))
+		}
+	}
+	return out
+}
+
+func TestFlowBankSwitch(t *testing.T) {
+	tests := []struct {
+		name     string
+		cartType byte
+		code     []byte
+		want     []string
+	}{
+		{
+			name:     "MBC1",
+			cartType: 0x01,
+			code: []byte{
+				0x3E, 0x02, // LD A,2
+				0xEA, 0x00, 0x20, // LD [$2000],A
+				0xCD, 0x00, 0x40, // CALL $4000
+				0x3E, 0x03, // LD A,3
+				0xEA, 0xFF, 0x3F, // LD [$3FFF],A
+				0xCD, 0x10, 0x40, // CALL $4010
+				0x18, 0xFE, // JR @+0
+			},
+			want: []string{"call L_02_4000", "call L_03_4010"},
+		},
+		{
+			name:     "MBC3 bank 0",
+			cartType: 0x13,
+			code: []byte{
+				0xAF, // XOR A
+				0xEA, 0x00, 0x30, // LD [$3000],A
+				0xCD, 0x00, 0x40, // CALL $4000
+				0x18, 0xFE, // JR @+0
+			},
+			// Bank 0 selects bank 1.
+			want: []string{"call L_01_4000"},
+		},
+		{
+			name:     "MBC5",
+			cartType: 0x19,
+			code: []byte{
+				0x3E, 0x02, // LD A,2
+				0xEA, 0x00, 0x20, // LD [$2000],A
+				0xAF, // XOR A
+				0xEA, 0x00, 0x30, // LD [$3000],A, bit 8
+				0xCD, 0x00, 0x40, // CALL $4000
+				0x3E, 0x01, // LD A,1
+				0xEA, 0x00, 0x30, // LD [$3000],A, bank $102
+				0xCD, 0x10, 0x40, // CALL $4010
+				0x18, 0xFE, // JR @+0
+			},
+			want: []string{"call L_02_4000", "call $4010"},
+		},
+		{
+			name:     "unknown bank",
+			cartType: 0x01,
+			code: []byte{
+				0xCD, 0x00, 0x40, // CALL $4000
+				0x18, 0xFE, // JR @+0
+			},
+			want: []string{"call $4000"},
+		},
+	}
+	for _, test := range tests {
+		l := Disassemble(bankTestROM(test.cartType, test.code...), Options{})
+		if got := strings.Join(calls(l), "; "); got != strings.Join(test.want, "; ") {
+			t.Errorf("%s: got %s, want %s", test.name, got, strings.Join(test.want, "; "))
+		}
+	}
+}
+
+func TestBankedListing(t *testing.T) {
+	rom := bankTestROM(0x01,
+		0x3E, 0x02, // LD A,2
+		0xEA, 0x00, 0x20, // LD [$2000],A
+		0xCD, 0x00, 0x40, // CALL $4000
+		0x18, 0xFE, // JR @+0
+	)
+	l := Disassemble(rom, Options{})
+	in := lines(l)[Location{2, 0x4000}]
+	if in.Op == nil || in.Op.Mnemonic != "RET" || in.Offset != 2 * bankSize {
+		t.Errorf("02:4000 = %+v, want the RET at offset %#x", in, 2 * bankSize)
+	}
+	// Not called, bank 3 is data.
+	if in := lines(l)[Location{3, 0x4000}]; in.Op != nil {
+		t.Errorf("03:4000 decoded as %s, want data", in.Op.Mnemonic)
+	}
+	var out strings.Builder
+	if _, err := l.WriteTo(&out); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"SECTION \"ROM Bank $01\", ROMX[$4000], BANK[$01]",
+		"SECTION \"ROM Bank $02\", ROMX[$4000], BANK[$02]\n\nL_02_4000:\n\tret                     ; 02:4000",
+		"SECTION \"ROM Bank $03\", ROMX[$4000], BANK[$03]",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("listing doesn't contain %q", want)
+		}
+	}
+}
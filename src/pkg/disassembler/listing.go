@@ -0,0 +1,197 @@
+package disassembler
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Listing is a disassembled ROM.
+type Listing struct {
+	Instructions []Instruction
+	opts Options
+	// Labels defined in the listing, by location.
+	labels map[Location]string
+	// Bank 1 is the only one mapped at 0x4000-0x7FFF.
+	singleBank bool
+}
+
+func generatedLabel(loc Location) string {
+	if loc.Bank == 0 {
+		return fmt.Sprintf("L_%04x", loc.Addr)
+	}
+	return fmt.Sprintf("L_%02x_%04x", loc.Bank, loc.Addr)
+}
+
+// WriteTo writes the listing as RGBDS assembly, a section per bank, each
+// instruction commented with its bank:address.
+func (l *Listing) WriteTo(w io.Writer) (int64, error) {
+	// Symbols used as operands must be defined for the listing to assemble,
+	// the ones outside it (RAM, IO registers...) become constants.
+	constants := map[string]uint16{}
+	var body strings.Builder
+	for i := range l.Instructions {
+		in := &l.Instructions[i]
+		loc := Location{in.Bank, in.Addr}
+		if i == 0 || in.Bank != l.Instructions[i - 1].Bank {
+			if i > 0 {
+				body.WriteString("\n")
+			}
+			writeSection(&body, loc)
+		}
+		if label, ok := l.labels[loc]; ok {
+			fmt.Fprintf(&body, "%s:\n", label)
+		}
+		fmt.Fprintf(&body, "\t%-23s ; %v\n", l.text(in, constants), loc)
+	}
+
+	var out strings.Builder
+	if len(constants) > 0 {
+		names := make([]string, 0, len(constants))
+		for name := range constants {
+			names = append(names, name)
+		}
+		sort.Slice(names, func(i, j int) bool {
+			return constants[names[i]] < constants[names[j]]
+		})
+		for _, name := range names {
+			fmt.Fprintf(&out, "%s EQU $%04x\n", name, constants[name])
+		}
+		out.WriteString("\n")
+	}
+	out.WriteString(body.String())
+	n, err := io.WriteString(w, out.String())
+	return int64(n), err
+}
+
+// Starts the section of a bank, at loc for the first bank of a ROM which
+// doesn't start at 0.
+func writeSection(w io.Writer, loc Location) {
+	if loc.Bank == 0 {
+		fmt.Fprintf(w, "SECTION \"ROM Bank $00\", ROM0[$%04x]\n\n", loc.Addr)
+	} else {
+		fmt.Fprintf(w, "SECTION \"ROM Bank $%02x\", ROMX[$%04x], BANK[$%02x]\n\n", loc.Bank, loc.Addr, loc.Bank)
+	}
+}
+
+// Text formats an instruction in RGBDS syntax.
+func (l *Listing) Text(in *Instruction) string {
+	return l.text(in, map[string]uint16{})
+}
+
+// Formats an instruction, adding the symbols it refers to which aren't
+// labels of the listing to constants.
+func (l *Listing) text(in *Instruction, constants map[string]uint16) string {
+	if in.Pointer {
+		return "dw " + l.target(in)
+	}
+	op := in.Op
+	if op == nil {
+		return dataText(in.Bytes)
+	}
+	switch in.Bytes[0] {
+	case 0x10:
+		// rgbasm always follows STOP with a 0 byte.
+		if in.Bytes[1] != 0 {
+			return dataText(in.Bytes)
+		}
+		return "stop"
+	case 0xF8:
+		return fmt.Sprintf("ld hl, sp%+d", int8(in.Imm))
+	}
+	mnemonic := strings.ToLower(op.Mnemonic)
+	var operands []string
+	for _, o := range op.Operands {
+		var s string
+		switch o.Name {
+		case "n8":
+			s = fmt.Sprintf("$%02x", in.Imm)
+		case "n16":
+			s = l.value(in, in.Imm, constants)
+		case "a16":
+			if in.HasTarget {
+				s = l.target(in)
+			} else {
+				s = l.value(in, in.Imm, constants)
+			}
+		case "a8":
+			s = l.value(in, 0xFF00 + in.Imm, constants)
+		case "e8":
+			if in.HasTarget {
+				s = l.target(in)
+			} else {
+				s = fmt.Sprintf("%d", int8(in.Imm))
+			}
+		default:
+			// Registers, conditions, bit numbers and RST vectors.
+			s = strings.ToLower(o.Name)
+		}
+		if o.Increment {
+			s += "+"
+		} else if o.Decrement {
+			s += "-"
+		}
+		if !o.Immediate {
+			if s == "c" {
+				// LD [C],A is spelled LDH [C],A in RGBDS.
+				mnemonic = "ldh"
+			}
+			s = "[" + s + "]"
+		}
+		operands = append(operands, s)
+	}
+	if len(operands) == 0 {
+		return mnemonic
+	}
+	return mnemonic + " " + strings.Join(operands, ", ")
+}
+
+// Jump target: its label, or a number when it isn't an instruction of the
+// listing.
+func (l *Listing) target(in *Instruction) string {
+	if label, ok := l.labels[Location{in.TargetBank, in.Target}]; ok {
+		return label
+	}
+	if in.Op != nil && in.Op.Mnemonic == "JR" {
+		// Relative to the start of the instruction, so it assembles to
+		// the same offset.
+		return fmt.Sprintf("@%+d", int16(in.Target - in.Addr))
+	}
+	return fmt.Sprintf("$%04x", in.Target)
+}
+
+// An address or 16-bit number used by in, named when a symbol is defined
+// exactly there.
+func (l *Listing) value(in *Instruction, v uint16, constants map[string]uint16) string {
+	var bank uint16
+	if v >= 0x4000 && v < 0x8000 {
+		// The bank of the instruction, or the only one.
+		switch {
+		case in.Bank != 0:
+			bank = in.Bank
+		case l.singleBank:
+			bank = 1
+		default:
+			return fmt.Sprintf("$%04x", v)
+		}
+	}
+	if label, ok := l.labels[Location{bank, v}]; ok {
+		return label
+	}
+	name, ok := l.opts.Symbols.Name(bank, v)
+	// Local labels can't be defined as constants.
+	if !ok || strings.Contains(name, ".") {
+		return fmt.Sprintf("$%04x", v)
+	}
+	constants[name] = v
+	return name
+}
+
+func dataText(b []byte) string {
+	parts := make([]string, len(b))
+	for i, v := range b {
+		parts[i] = fmt.Sprintf("$%02x", v)
+	}
+	return "db " + strings.Join(parts, ", ")
+}
@@ -0,0 +1,75 @@
+package gameboy
+
+/*
+APU register file (0xFF10-0xFF3F).
+
+Sound generation is not emulated yet, but the registers are mapped with their
+hardware read masks so games polling them see the expected values. While the
+APU is powered off (NR52 bit 7 clear) all registers but NR52 and wave RAM
+ignore writes.
+*/
+
+const (
+	NR52_ADDR = 0xFF26
+	WAVE_RAM_ADDR = 0xFF30
+)
+
+// Bits of each sound register which can be read back, 0xFF10-0xFF25.
+var apuReadMasks = [0x16]byte{
+	0x7F, 0xC0, 0xFF, 0x00, 0x40, // NR10-NR14
+	0x00, 0xC0, 0xFF, 0x00, 0x40, // unused, NR21-NR24
+	0x80, 0x00, 0x60, 0x00, 0x40, // NR30-NR34
+	0x00, 0x00, 0xFF, 0xFF, 0x40, // unused, NR41-NR44
+	0xFF, 0xFF, // NR50, NR51
+}
+
+type APU struct {
+	regs [0x16]*IORegister
+	nr52 *IORegister
+	waveRAM [0x10]*IORegister
+
+	gb *GB
+}
+
+func (apu *APU) Init(gb *GB) {
+	apu.gb = gb
+	mmu := gb.MMU
+	for i, mask := range apuReadMasks {
+		// 0xFF15 and 0xFF1F are unmapped.
+		if i == 0x05 || i == 0x0F {
+			continue
+		}
+		reg := mmu.RegisterIO(0xFF10 + uint16(i), &IORegister{ReadMask: mask, WriteMask: 0xFF})
+		reg.OnWrite = func(val byte) {
+			if apu.powered() {
+				reg.Store(val)
+			}
+		}
+		apu.regs[i] = reg
+	}
+	// Channel status bits are read-only.
+	apu.nr52 = mmu.RegisterIO(NR52_ADDR, &IORegister{ReadMask: 0x8F, WriteMask: 0x80})
+	apu.nr52.OnWrite = func(val byte) {
+		apu.nr52.Store(val)
+		if !apu.powered() {
+			apu.powerOff()
+		}
+	}
+	for i := range apu.waveRAM {
+		apu.waveRAM[i] = mmu.RegisterIO(WAVE_RAM_ADDR + uint16(i), &IORegister{ReadMask: 0xFF, WriteMask: 0xFF})
+	}
+}
+
+func (apu *APU) powered() bool {
+	return apu.nr52.Value() & 0x80 != 0
+}
+
+// Powering the APU off clears every sound register.
+func (apu *APU) powerOff() {
+	for _, reg := range apu.regs {
+		if reg != nil {
+			reg.Set(0)
+		}
+	}
+	apu.nr52.Set(0)
+}
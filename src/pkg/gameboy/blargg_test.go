@@ -0,0 +1,204 @@
+package gameboy
+
+/*
+Blargg's test ROMs, run headlessly. The ROMs aren't redistributable, copy
+them under testdata/blargg keeping the layout of the original archives:
+
+	testdata/blargg/cpu_instrs/cpu_instrs.gb
+	testdata/blargg/cpu_instrs/individual/01-special.gb
+	...
+	testdata/blargg/halt_bug.gb
+
+Missing ROMs are skipped, and so are ROMs larger than 32 KiB, which need a
+memory bank controller. The combined cpu_instrs.gb and mem_timing.gb are left
+out until there is one. Results are read both from the serial port, where the
+ROMs print "Passed" or "Failed", and from the signature at 0xA000 the newer
+ROMs leave in cartridge RAM: 0xA001-0xA003 hold DE B0 61, 0xA000 the status
+(0x80 while running, 0 once passed) and 0xA004 the output text.
+*/
+import (
+	"bytes"
+	"gopherboy/pkg/common"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const blarggDir = "testdata/blargg"
+
+// Checked once per frame, the signature doesn't need more.
+const blarggPollCycles = 70224
+
+var blarggROMs = []struct {
+	path string
+	// Emulated seconds before giving up.
+	timeout int
+}{
+	{"cpu_instrs/individual/01-special.gb", 10},
+	{"cpu_instrs/individual/02-interrupts.gb", 10},
+	{"cpu_instrs/individual/03-op sp,hl.gb", 10},
+	{"cpu_instrs/individual/04-op r,imm.gb", 10},
+	{"cpu_instrs/individual/05-op rp.gb", 10},
+	{"cpu_instrs/individual/06-ld r,r.gb", 10},
+	{"cpu_instrs/individual/07-jr,jp,call,ret,rst.gb", 10},
+	{"cpu_instrs/individual/08-misc instrs.gb", 10},
+	{"cpu_instrs/individual/09-op r,r.gb", 10},
+	{"cpu_instrs/individual/10-bit ops.gb", 10},
+	{"cpu_instrs/individual/11-op a,(hl).gb", 20},
+	{"instr_timing/instr_timing.gb", 10},
+	{"mem_timing/individual/01-read_timing.gb", 10},
+	{"mem_timing/individual/02-write_timing.gb", 10},
+	{"mem_timing/individual/03-modify_timing.gb", 10},
+	{"halt_bug.gb", 10},
+}
+
+type blarggResult struct {
+	done   bool
+	passed bool
+	output string
+}
+
+// Reads the result signature from cartridge RAM.
+func blarggSignature(gb *GB) blarggResult {
+	mmu := gb.MMU
+	if mmu.Peek(0xA001) != 0xDE || mmu.Peek(0xA002) != 0xB0 || mmu.Peek(0xA003) != 0x61 {
+		return blarggResult{}
+	}
+	status := mmu.Peek(0xA000)
+	if status == 0x80 {
+		return blarggResult{}
+	}
+	var text strings.Builder
+	for addr := uint16(0xA004); addr < 0xC000 && mmu.Peek(addr) != 0; addr++ {
+		text.WriteByte(mmu.Peek(addr))
+	}
+	return blarggResult{done: true, passed: status == 0, output: text.String()}
+}
+
+func blarggSerial(out *bytes.Buffer) blarggResult {
+	s := out.String()
+	switch {
+	case strings.Contains(s, "Passed"):
+		return blarggResult{done: true, passed: true, output: s}
+	case strings.Contains(s, "Failed"):
+		return blarggResult{done: true, output: s}
+	}
+	return blarggResult{}
+}
+
+// Runs gb until a result shows up or timeout emulated seconds have passed.
+// The result isn't done on timeout, its output is the serial output so far.
+func runBlargg(t *testing.T, gb *GB, timeout int) blarggResult {
+	var serial bytes.Buffer
+	gb.Serial.Output = &serial
+	limit := uint64(timeout) * common.ClkFrequency
+	next := uint64(0)
+	for gb.cycles < limit {
+		if _, err := gb.Step(); err != nil {
+			t.Fatalf("emulation stopped: %v\nserial output:\n%s\n%s", err, serial.String(), gb.FormatBacktrace())
+		}
+		if gb.cycles < next {
+			continue
+		}
+		next = gb.cycles + blarggPollCycles
+		result := blarggSerial(&serial)
+		if !result.done {
+			result = blarggSignature(gb)
+		}
+		if result.done {
+			return result
+		}
+	}
+	return blarggResult{output: serial.String()}
+}
+
+func TestBlargg(t *testing.T) {
+	for _, rom := range blarggROMs {
+		t.Run(rom.path, func(t *testing.T) {
+			path := filepath.Join(blarggDir, filepath.FromSlash(rom.path))
+			info, err := os.Stat(path)
+			if err != nil {
+				t.Skipf("%s not found", path)
+			}
+			if info.Size() > 0x8000 {
+				t.Skipf("%s is banked, there is no memory bank controller yet", path)
+			}
+			if testing.Short() {
+				t.Skip("skipping test ROM in short mode")
+			}
+			t.Parallel()
+			gb := NewGB("", path, false)
+			gb.SkipBoot(ModelDMG)
+			if err := gb.Init(); err != nil {
+				t.Fatal(err)
+			}
+			result := runBlargg(t, gb, rom.timeout)
+			switch {
+			case !result.done:
+				t.Errorf("no result after %d emulated seconds, serial output:\n%s", rom.timeout, result.output)
+			case !result.passed:
+				t.Errorf("failed:\n%s", result.output)
+			}
+		})
+	}
+}
+
+// A ROM leaving the result signature with the given status and "ok" as the
+// output text, the way the test ROMs do.
+func blarggSignatureROM(status byte) []byte {
+	var code []byte
+	store := func(addr uint16, val byte) {
+		// LD A,val / LD [addr],A
+		code = append(code, 0x3E, val, 0xEA, byte(addr), byte(addr >> 8))
+	}
+	store(0xA000, 0x80)
+	store(0xA001, 0xDE)
+	store(0xA002, 0xB0)
+	store(0xA003, 0x61)
+	store(0xA004, 'o')
+	store(0xA005, 'k')
+	store(0xA000, status)
+	// JR -2
+	code = append(code, 0x18, 0xFE)
+	return testROM(code...)
+}
+
+func TestBlarggSignature(t *testing.T) {
+	tests := []struct {
+		status byte
+		passed bool
+	}{
+		{0x00, true},
+		{0x01, false},
+	}
+	for _, test := range tests {
+		result := runBlargg(t, newTestGB(t, blarggSignatureROM(test.status)), 1)
+		if !result.done || result.passed != test.passed || result.output != "ok" {
+			t.Errorf("status %#02x: got %+v, want done, passed %v, output \"ok\"", test.status, result, test.passed)
+		}
+	}
+	// Still running.
+	result := runBlargg(t, newTestGB(t, blarggSignatureROM(0x80)), 1)
+	if result.done {
+		t.Errorf("status 0x80: got %+v, want no result", result)
+	}
+}
+
+func TestBlarggSerial(t *testing.T) {
+	tests := []struct {
+		out    string
+		done   bool
+		passed bool
+	}{
+		{"cpu_instrs\n\n01:ok  ", false, false},
+		{"cpu_instrs\n\n01:ok  \n\nPassed all tests\n", true, true},
+		{"02-interrupts\n\nTimer doesn't work\nFailed #4\n", true, false},
+	}
+	for _, test := range tests {
+		result := blarggSerial(bytes.NewBufferString(test.out))
+		if result.done != test.done || result.passed != test.passed {
+			t.Errorf("%q: got %+v, want done %v, passed %v", test.out, result, test.done, test.passed)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package gameboy
+
+/*
+Post-boot machine state used to skip the boot ROM.
+
+The boot ROM leaves the CPU registers and a handful of IO registers in a
+well-known state before jumping to the cartridge entry point at 0x0100. The
+values below are the ones documented in Pan Docs ("Power Up Sequence") for
+each hardware model. Registers whose values depend on the exact boot timing
+(e.g. DIV on SGB/CGB) are set to the most commonly observed value.
+*/
+import "fmt"
+
+type Model uint8
+
+const (
+	ModelDMG0 Model = iota
+	ModelDMG
+	ModelMGB
+	ModelSGB
+	ModelCGB
+)
+
+func ParseModel(s string) (Model, error) {
+	switch s {
+	case "dmg0":
+		return ModelDMG0, nil
+	case "dmg":
+		return ModelDMG, nil
+	case "mgb":
+		return ModelMGB, nil
+	case "sgb":
+		return ModelSGB, nil
+	case "cgb":
+		return ModelCGB, nil
+	}
+	return ModelDMG, fmt.Errorf("unknown model %q (want dmg0, dmg, mgb, sgb or cgb)", s)
+}
+
+func (m Model) String() string {
+	switch m {
+	case ModelDMG0:
+		return "dmg0"
+	case ModelMGB:
+		return "mgb"
+	case ModelSGB:
+		return "sgb"
+	case ModelCGB:
+		return "cgb"
+	}
+	return "dmg"
+}
+
+type postBootState struct {
+	AF, BC, DE, HL uint16
+	// IO register values, indexed by address.
+	io map[uint16]byte
+}
+
+// IO register values shared by all models, overridden per model below.
+var postBootIO = map[uint16]byte{
+	0xFF00: 0xCF, // P1
+	0xFF01: 0x00, // SB
+	0xFF02: 0x7E, // SC
+	0xFF04: 0xAB, // DIV
+	0xFF05: 0x00, // TIMA
+	0xFF06: 0x00, // TMA
+	0xFF07: 0xF8, // TAC
+	0xFF0F: 0xE1, // IF
+	0xFF10: 0x80, // NR10
+	0xFF11: 0xBF, // NR11
+	0xFF12: 0xF3, // NR12
+	0xFF13: 0xFF, // NR13
+	0xFF14: 0xBF, // NR14
+	0xFF16: 0x3F, // NR21
+	0xFF17: 0x00, // NR22
+	0xFF18: 0xFF, // NR23
+	0xFF19: 0xBF, // NR24
+	0xFF1A: 0x7F, // NR30
+	0xFF1B: 0xFF, // NR31
+	0xFF1C: 0x9F, // NR32
+	0xFF1D: 0xFF, // NR33
+	0xFF1E: 0xBF, // NR34
+	0xFF20: 0xFF, // NR41
+	0xFF21: 0x00, // NR42
+	0xFF22: 0x00, // NR43
+	0xFF23: 0xBF, // NR44
+	0xFF24: 0x77, // NR50
+	0xFF25: 0xF3, // NR51
+	0xFF26: 0xF1, // NR52
+	0xFF40: 0x91, // LCDC
+	0xFF41: 0x85, // STAT
+	0xFF42: 0x00, // SCY
+	0xFF43: 0x00, // SCX
+	0xFF44: 0x00, // LY
+	0xFF45: 0x00, // LYC
+	0xFF46: 0xFF, // DMA
+	0xFF47: 0xFC, // BGP
+	0xFF4A: 0x00, // WY
+	0xFF4B: 0x00, // WX
+	0xFFFF: 0x00, // IE
+}
+
+var postBootStates = map[Model]postBootState{
+	ModelDMG0: {
+		AF: 0x0100, BC: 0xFF13, DE: 0x00C1, HL: 0x8403,
+		io: map[uint16]byte{0xFF04: 0x18, 0xFF41: 0x81},
+	},
+	ModelDMG: {
+		AF: 0x01B0, BC: 0x0013, DE: 0x00D8, HL: 0x014D,
+	},
+	ModelMGB: {
+		AF: 0xFFB0, BC: 0x0013, DE: 0x00D8, HL: 0x014D,
+	},
+	ModelSGB: {
+		AF: 0x0100, BC: 0x0014, DE: 0x0000, HL: 0xC060,
+		io: map[uint16]byte{0xFF04: 0x00, 0xFF26: 0xF0},
+	},
+	ModelCGB: {
+		AF: 0x1180, BC: 0x0000, DE: 0xFF56, HL: 0x000D,
+		io: map[uint16]byte{0xFF02: 0x7F, 0xFF04: 0x00, 0xFF46: 0x00},
+	},
+}
+
+// Puts the machine in the state the boot ROM of the given model leaves it in.
+func (gb *GB) applyPostBootState(model Model) {
+	state := postBootStates[model]
+	cpu := gb.CPU
+	cpu.AF.Set(state.AF)
+	// DMG and MGB clear H and C when the cartridge header checksum is zero.
+	if (model == ModelDMG || model == ModelMGB) && gb.MMU.Peek(0x014D) == 0 {
+		cpu.AF.SetLo(0x80)
+	}
+	cpu.BC.Set(state.BC)
+	cpu.DE.Set(state.DE)
+	cpu.HL.Set(state.HL)
+	cpu.SP.Set(0xFFFE)
+	cpu.PC = 0x0100
+
+	for addr, val := range postBootIO {
+		gb.MMU.setIO(addr, val)
+	}
+	for addr, val := range state.io {
+		gb.MMU.setIO(addr, val)
+	}
+	// DIV is the upper byte of the timer's internal counter.
+	div, ok := state.io[DIV_ADDR]
+	if !ok {
+		div = postBootIO[DIV_ADDR]
+	}
+	gb.Timer.div = uint16(div) << 8
+	// The boot ROM unmaps itself by writing to the BANK register.
+	gb.MMU.setIO(BANK_ADDR, 0x01)
+	gb.MMU.biosEnabled = false
+}
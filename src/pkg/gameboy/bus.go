@@ -0,0 +1,24 @@
+package gameboy
+
+// The system map the CPU sees inside a GB: memory goes through the MMU, and
+// the other devices are advanced by the clocks the CPU spends.
+type systemBus struct {
+	gb *GB
+}
+
+func (b systemBus) Read(addr uint16) byte {
+	return b.gb.MMU.ReadAt(addr)
+}
+
+func (b systemBus) Write(addr uint16, val byte) {
+	b.gb.MMU.WriteAt(addr, val)
+}
+
+func (b systemBus) Peek(addr uint16) byte {
+	return b.gb.MMU.Peek(addr)
+}
+
+func (b systemBus) Tick(cycles int) {
+	b.gb.tickDevices(cycles)
+	b.gb.cycles += uint64(cycles)
+}
@@ -0,0 +1,142 @@
+package gameboy
+
+/*
+Shadow call stack.
+
+The CPU keeps its own record of the calls in progress next to the real stack
+in memory, so a backtrace doesn't depend on guessing the stack layout. A
+frame is pushed when a CALL or RST is taken or an interrupt is dispatched,
+and popped when a RET or RETI returns through it.
+
+Code is free to manipulate SP directly: return addresses get discarded with
+POP, ADD SP or LD SP, or overwritten before returning. The shadow stack is
+reconciled with SP after every instruction, and every frame dropped without
+a matching return is recorded as a mismatch.
+*/
+import (
+	"fmt"
+	"strings"
+)
+
+type FrameKind uint8
+
+const (
+	FrameCall FrameKind = iota
+	FrameRST
+	FrameInterrupt
+)
+
+func (k FrameKind) String() string {
+	switch k {
+	case FrameCall:
+		return "call"
+	case FrameRST:
+		return "rst"
+	case FrameInterrupt:
+		return "interrupt"
+	}
+	return fmt.Sprintf("FrameKind(%d)", uint8(k))
+}
+
+type Frame struct {
+	Kind FrameKind
+	// Address of the CALL or RST, or of the instruction an interrupt
+	// preempted.
+	CallSite uint16
+	// Address jumped to.
+	Target uint16
+	ReturnAddr uint16
+	// Stack address holding the return address.
+	SP uint16
+}
+
+type StackMismatch struct {
+	Frame Frame
+	// Address of the instruction after which the frame was dropped.
+	PC uint16
+	Reason string
+}
+
+func (m StackMismatch) String() string {
+	return fmt.Sprintf("%s frame from %#04x dropped at pc %#04x: %s", m.Frame.Kind, m.Frame.CallSite, m.PC, m.Reason)
+}
+
+// Oldest frames are forgotten past this depth, e.g. with runaway recursion.
+const maxCallDepth = 1024
+
+// Number of recent mismatches kept.
+const maxStackMismatches = 16
+
+// Backtrace returns the frames in progress, innermost first.
+func (cpu *CPU) Backtrace() []Frame {
+	frames := make([]Frame, len(cpu.callStack))
+	for i, f := range cpu.callStack {
+		frames[len(frames) - 1 - i] = f
+	}
+	return frames
+}
+
+// StackMismatches returns the most recent frames dropped without a matching
+// return, oldest first.
+func (cpu *CPU) StackMismatches() []StackMismatch {
+	return cpu.stackMismatches
+}
+
+func (cpu *CPU) pushFrame(f Frame) {
+	if len(cpu.callStack) == maxCallDepth {
+		cpu.callStack = cpu.callStack[1:]
+	}
+	cpu.callStack = append(cpu.callStack, f)
+}
+
+func (cpu *CPU) dropFrame(pc uint16, reason string) {
+	n := len(cpu.callStack)
+	if len(cpu.stackMismatches) == maxStackMismatches {
+		cpu.stackMismatches = cpu.stackMismatches[1:]
+	}
+	cpu.stackMismatches = append(cpu.stackMismatches, StackMismatch{Frame: cpu.callStack[n-1], PC: pc, Reason: reason})
+	cpu.callStack = cpu.callStack[:n-1]
+}
+
+// Updates the shadow stack after the instruction at addr was executed with
+// the stack pointer at oldSP.
+func (cpu *CPU) trackCallStack(opcode byte, addr, oldSP uint16) {
+	sp := cpu.SP.value
+	switch {
+	case IsCall(opcode) && sp == oldSP - 2:
+		f := Frame{Kind: FrameCall, CallSite: addr, Target: cpu.PC, ReturnAddr: addr + 3, SP: sp}
+		if opcode & 0xC7 == 0xC7 {
+			f.Kind = FrameRST
+			f.ReturnAddr = addr + 1
+		}
+		cpu.pushFrame(f)
+		return
+	case IsReturn(opcode) && sp == oldSP + 2:
+		// A return through an address which wasn't pushed by a call is a
+		// computed jump ("push hl / ret") and doesn't end a frame.
+		if n := len(cpu.callStack); n > 0 && cpu.callStack[n-1].SP == oldSP {
+			if f := cpu.callStack[n-1]; f.ReturnAddr != cpu.PC {
+				cpu.dropFrame(addr, fmt.Sprintf("returned to %#04x instead of %#04x", cpu.PC, f.ReturnAddr))
+			} else {
+				cpu.callStack = cpu.callStack[:n-1]
+			}
+		}
+	}
+	for n := len(cpu.callStack); n > 0 && cpu.callStack[n-1].SP < sp; n-- {
+		cpu.dropFrame(addr, "return address popped without a return")
+	}
+}
+
+// FormatBacktrace renders the current backtrace, one frame per line and
+// innermost first, e.g.
+//
+//	#0  0x0203 <Sub+$3>
+//	#1  0x0101 <Main+$1>  call 0x0200 <Sub>
+func (gb *GB) FormatBacktrace() string {
+	var out strings.Builder
+	fmt.Fprintf(&out, "#0  %s\n", gb.FormatAddr(gb.CPU.PC))
+	for i, f := range gb.CPU.Backtrace() {
+		fmt.Fprintf(&out, "#%-2d %s  %s %s\n", i + 1, gb.FormatAddr(f.CallSite), f.Kind, gb.FormatAddr(f.Target))
+	}
+	return out.String()
+}
@@ -53,8 +53,18 @@ func (r *Register) applyMask() {
 	}
 }
 
+// Bus is everything the CPU is connected to. Read and Write are the CPU's own
+// accesses, Peek reads without side effects for debugging. Tick is called
+// with the clocks the CPU spends, so the rest of the system can keep up.
+type Bus interface {
+	Read(addr uint16) byte
+	Write(addr uint16, val byte)
+	Peek(addr uint16) byte
+	Tick(cycles int)
+}
+
 type CPU struct {
-	MMU *MMU
+	Bus Bus
 	/* registers */
 	AF Register
 	BC Register
@@ -64,13 +74,52 @@ type CPU struct {
 	PC uint16
 	// Initialized to 0xFFFE, but usually overriden by program
 	SP Register
+	// Interrupt master enable.
+	IME bool
+	// Machine the CPU is part of, nil when running against a bare Bus.
 	gb *GB
 	debug bool
+
+	IllegalOpcodePolicy IllegalOpcodePolicy
+	// Set once an illegal opcode hard-locked the CPU.
+	locked bool
+	// Set by HALT until an interrupt is requested.
+	halted bool
+	// Set by EI, which enables interrupts after the following instruction.
+	imePending bool
+	// Error raised by the last instruction, returned from Tick.
+	fault error
+	// Clocks spent on the current instruction so far.
+	clocks int
+	// Execution trace, nil unless tracing.
+	trace *traceWriter
+	// Shadow call stack, outermost frame first.
+	callStack []Frame
+	stackMismatches []StackMismatch
+}
+
+// Each memory access takes an M-cycle, at the end of which the byte is read
+// or written. The rest of the system is advanced first, so it sees the access
+// at the right time within the instruction.
+func (cpu *CPU) read(addr uint16) byte {
+	cpu.idle()
+	return cpu.Bus.Read(addr)
+}
+
+func (cpu *CPU) write(addr uint16, val byte) {
+	cpu.idle()
+	cpu.Bus.Write(addr, val)
+}
+
+// An M-cycle without memory access.
+func (cpu *CPU) idle() {
+	cpu.Bus.Tick(4)
+	cpu.clocks += 4
 }
 
 // Read the following byte from PC and advance the pointer.
 func (cpu *CPU) popPC8() byte {
-	val := cpu.MMU.ReadAt(cpu.PC)
+	val := cpu.read(cpu.PC)
 	cpu.PC += 1
 	return val
 }
@@ -122,8 +171,8 @@ func (cpu *CPU) testH() bool {
 
 func (cpu *CPU) updateInstrPlaceholder(instr string) string {
 	if strings.Contains(instr, "a16") || strings.Contains(instr, "n16") {
-		b1 := uint16(cpu.MMU.ReadAt(cpu.PC))
-		b2 := uint16(cpu.MMU.ReadAt(cpu.PC + 1))
+		b1 := uint16(cpu.Bus.Peek(cpu.PC))
+		b2 := uint16(cpu.Bus.Peek(cpu.PC + 1))
 		addr := b2 << 8 | b1
 		newInstr := strings.ReplaceAll(instr, "a16", fmt.Sprintf("a16 {%#4x}", addr))
 		newInstr = strings.ReplaceAll(newInstr, "n16", fmt.Sprintf("n16 {%#4x}", addr))
@@ -133,6 +182,11 @@ func (cpu *CPU) updateInstrPlaceholder(instr string) string {
 }
 
 func (cpu *CPU) printRegisterDump() {
+	fmt.Print(cpu.RegisterDump())
+}
+
+// RegisterDump formats all registers and flags for debug output.
+func (cpu *CPU) RegisterDump() string {
 	out := fmt.Sprintf(
 		"B: %#4x\tC: %#4x\nD: %#4x\tE: %#4x\nH: %#4x\tL: %#4x\nA: %#4x\n",
 		cpu.BC.Hi(),
@@ -144,8 +198,8 @@ func (cpu *CPU) printRegisterDump() {
 		cpu.AF.Hi(),
 	)
 	reserved := fmt.Sprintf(
-		"PC: %#4x\tSP: %x\n",
-		cpu.PC,
+		"PC: %s\tSP: %x\n",
+		cpu.gb.FormatAddr(cpu.PC),
 		cpu.SP.value,
 	)
 	flags := fmt.Sprintf(
@@ -155,7 +209,7 @@ func (cpu *CPU) printRegisterDump() {
 		cpu.testN(),
 		cpu.testH(),
 	)
-	fmt.Printf("[REGISTERS]\n%s%s[FLAGS]\n%s\n",out, reserved, flags)
+	return fmt.Sprintf("[REGISTERS]\n%s%s[FLAGS]\n%s\n",out, reserved, flags)
 }
 
 
@@ -168,32 +222,78 @@ func (cpu *CPU) Init(gb *GB) error {
 	return nil
 }
 
+// Halted reports whether the CPU is stopped by HALT, running no instructions
+// until an interrupt is requested.
+func (cpu *CPU) Halted() bool {
+	return cpu.halted
+}
+
 // TODO: Emulate a single CPU tick, return number of instruction cycles elapsed.
-func (cpu *CPU) Tick() int {
+func (cpu *CPU) Tick() (int, error) {
+	if cpu.locked {
+		// The rest of the system keeps running while the CPU is locked up.
+		cpu.Bus.Tick(4)
+		return 4, nil
+	}
+	if cpu.halted {
+		// Woken up by any requested interrupt, even with IME off. The
+		// interrupt is serviced before the next instruction when enabled.
+		cpu.Bus.Tick(4)
+		if cpu.Bus.Peek(IE_ADDR) & cpu.Bus.Peek(IF_ADDR) & 0x1F != 0 {
+			cpu.halted = false
+		}
+		return 4, nil
+	}
+	imePending := cpu.imePending
+	if cpu.trace != nil {
+		cpu.writeTrace()
+	}
+	cpu.clocks = 0
 	addr := cpu.PC
+	sp := cpu.SP.value
 	opcode := cpu.popPC8()
+	prefixed := opcode == 0xCB
 	opcodeStr := common.InstrDebugLookup[opcode]
 	instructionMapping := instructions
-	opcodeCyclesMapping := OpcodeCycles
-	if opcode == 0xCB {
+	opcodeCyclesMapping := common.OpcodeCycles
+	if prefixed {
 		addr = cpu.PC
 		opcode = cpu.popPC8()
 		opcodeStr = common.PrefixInstrDebugLookup[opcode]
 		instructionMapping = cbInstructions
-		opcodeCyclesMapping = CBOpcodeCycles
+		opcodeCyclesMapping = common.CBOpcodeCycles
+	}
+	// Operands have to be decoded before the instruction changes them.
+	var dInfo string
+	if cpu.debug {
+		dInfo = NewInstrInfo(opcode, addr, opcodeStr).DebugInfo(cpu)
 	}
-	ii := NewInstrInfo(opcode, addr, opcodeStr)
-	dInfo := ii.DebugInfo(cpu)
 	instructionMapping[opcode](cpu)
-	cycles := opcodeCyclesMapping[opcode] * 4
-	fmt.Print(dInfo)
+	if imePending && cpu.imePending {
+		cpu.IME = true
+		cpu.imePending = false
+	}
+	if !prefixed {
+		cpu.trackCallStack(opcode, addr, sp)
+	}
 	if cpu.debug {
+		fmt.Print(dInfo)
 		cpu.printRegisterDump()
 	}
-	return cycles
+	if cpu.fault != nil {
+		err := cpu.fault
+		cpu.fault = nil
+		return cpu.clocks, err
+	}
+	// Internal cycles at the end of the instruction (16-bit arithmetic, ...)
+	// aren't spelled out by the instructions.
+	for cpu.clocks < opcodeCyclesMapping[opcode] * 4 {
+		cpu.idle()
+	}
+	return cpu.clocks, nil
 }
 
 
-func NewCPU(mmu *MMU, debug bool) *CPU {
-	return &CPU{MMU: mmu,debug: debug} 
+func NewCPU(bus Bus, debug bool) *CPU {
+	return &CPU{Bus: bus,debug: debug} 
 }
\ No newline at end of file
@@ -0,0 +1,104 @@
+package gameboy
+
+import (
+	"fmt"
+	"gopherboy/pkg/common"
+	"testing"
+)
+
+func testCPU(code ...byte) (*CPU, *testBus) {
+	bus := &testBus{}
+	copy(bus.mem[:], code)
+	cpu := NewCPU(bus, false)
+	cpu.Init(nil)
+	return cpu, bus
+}
+
+func TestEIDelay(t *testing.T) {
+	// EI; NOP
+	cpu, _ := testCPU(0xFB, 0x00)
+	cpu.Tick()
+	if cpu.IME {
+		t.Error("IME set right after EI")
+	}
+	cpu.Tick()
+	if !cpu.IME {
+		t.Error("IME not set after the instruction following EI")
+	}
+
+	// EI; DI
+	cpu, _ = testCPU(0xFB, 0xF3, 0x00)
+	cpu.Tick()
+	cpu.Tick()
+	cpu.Tick()
+	if cpu.IME {
+		t.Error("DI didn't cancel the pending EI")
+	}
+}
+
+func TestHaltWakesOnInterrupt(t *testing.T) {
+	// HALT; INC A
+	cpu, bus := testCPU(0x76, 0x3C)
+	cpu.Tick()
+	for i := 0; i < 3; i++ {
+		if cycles, _ := cpu.Tick(); cycles != 4 || cpu.PC != 1 {
+			t.Fatalf("halted CPU took %d cycles, PC %#04x", cycles, cpu.PC)
+		}
+	}
+	// Requested but not enabled interrupts don't wake it up.
+	bus.mem[IF_ADDR] = 0x04
+	cpu.Tick()
+	if !cpu.halted {
+		t.Fatal("woken up by a disabled interrupt")
+	}
+	bus.mem[IE_ADDR] = 0x04
+	cpu.Tick()
+	cpu.Tick()
+	if cpu.halted || cpu.PC != 2 || cpu.AF.Hi() != 1 {
+		t.Errorf("halted %v, PC %#04x, A %#02x after waking up", cpu.halted, cpu.PC, cpu.AF.Hi())
+	}
+}
+
+// Runs every implemented opcode with each combination of flags, checking
+// the clocks it takes and the flags it sets against the generated tables.
+func TestInstructionsMatchOpcodes(t *testing.T) {
+	for _, prefixed := range []bool{false, true} {
+		for op := 0; op < 0x100; op++ {
+			info := common.Opcodes[op]
+			code := []byte{byte(op)}
+			name := fmt.Sprintf("%02x %s", op, info.Mnemonic)
+			if prefixed {
+				info = common.CBOpcodes[op]
+				code = []byte{0xCB, byte(op)}
+				name = fmt.Sprintf("cb %02x %s", op, info.Mnemonic)
+			} else if op == 0xCB {
+				continue
+			}
+			for f := 0; f < 0x100; f += 0x10 {
+				cpu, _ := testCPU(code...)
+				cpu.SP.Set(0xD000)
+				cpu.HL.Set(0xC000)
+				cpu.AF.Set(uint16(f))
+				clocks, err := cpu.Tick()
+				if err != nil {
+					// Not implemented yet.
+					break
+				}
+				if clocks != info.Cycles * 4 && clocks != info.CyclesNotTaken * 4 {
+					t.Errorf("%s with F %#02x took %d M-cycles, want %d or %d", name, f, clocks / 4, info.Cycles, info.CyclesNotTaken)
+				}
+				if info.Mnemonic == "POP" && info.Operands[0].Name == "AF" {
+					continue
+				}
+				got := byte(cpu.AF.Lo())
+				for i, effect := range info.Flags {
+					bit := byte(0x80) >> i
+					if effect == '-' && got & bit != byte(f) & bit || effect == '0' && got & bit != 0 || effect == '1' && got & bit == 0 {
+						t.Errorf("%s with F %#02x left F %#02x, want %s", name, f, got, info.Flags)
+						break
+					}
+				}
+			}
+		}
+	}
+}
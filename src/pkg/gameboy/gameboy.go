@@ -4,6 +4,7 @@ package gameboy
 import (
 	"fmt"
 	"gopherboy/pkg/common"
+	"gopherboy/pkg/symbols"
 	"time"
 )
 
@@ -12,45 +13,98 @@ const (
 	IF_ADDR = 0xFF0F
 )
 
+// Interrupt bit indexes within the IE and IF registers.
+const (
+	INT_VBLANK uint8 = 0
+	INT_STAT   uint8 = 1
+	INT_TIMER  uint8 = 2
+	INT_SERIAL uint8 = 3
+	INT_JOYPAD uint8 = 4
+)
+
 
 type GB struct {
 	CPU *CPU
 	MMU *MMU
+	PPU *PPU
+	Timer *Timer
+	Serial *Serial
+	APU *APU
+	Joypad *Joypad
 	// TODO(abhinandj): Add display
 	masterClk *time.Ticker
-	interruptsEnabled bool
+	// Clocks elapsed since power on.
+	cycles uint64
+	rewind *rewindBuffer
+	// Labels for debug output, nil when no symbol file is loaded.
+	Symbols *symbols.Table
 	debug bool
+	// Start at the cartridge entry point with the post-boot state of model.
+	skipBoot bool
+	model Model
+	// Start of the cartridge real time clock, set from movies so that they
+	// replay the same regardless of the host time.
+	rtcSeed uint64
 	// TODO: Memory access depends upon the current state (VBLANK, HBLANK etc)
 	// We should keep track of it here
 }
 
 func NewGB(bootRomPath, cartridgePath string, debug bool) (*GB) {
-	mmu := NewMMU(bootRomPath, cartridgePath)
-	return &GB{
-		CPU: NewCPU(mmu, debug), 
-		MMU: mmu, 
+	gb := &GB{
+		MMU: NewMMU(bootRomPath, cartridgePath), 
+		PPU: &PPU{},
+		Timer: &Timer{},
+		Serial: &Serial{},
+		APU: &APU{},
+		Joypad: &Joypad{},
 		debug: debug,
 	}
+	gb.CPU = NewCPU(systemBus{gb}, debug)
+	return gb
 }
 
+// NewGBFromROM builds a DMG running the cartridge rom from 0x0100, past the
+// boot ROM.
+func NewGBFromROM(rom []byte) (*GB, error) {
+	gb := NewGB("", "", false)
+	gb.MMU.cartridge = rom
+	gb.SkipBoot(ModelDMG)
+	if err := gb.Init(); err != nil {
+		return nil, err
+	}
+	return gb, nil
+}
+
+// SkipBoot makes Init start at 0x0100 with the register state the boot ROM of
+// the given model leaves behind, so no boot ROM is needed. Call before Init.
+func (gb *GB) SkipBoot(model Model) {
+	gb.skipBoot = true
+	gb.model = model
+}
+
+// Interrupt requests come from the devices, not the CPU, so they don't go
+// through watchpoints.
 func (gb *GB) RequestInterrupt(idx uint8) {
-	existingVal := gb.MMU.ReadAt(IF_ADDR)
+	existingVal := gb.MMU.Peek(IF_ADDR)
 	newVal := common.SetBitAtIndex(existingVal, idx)
-	gb.MMU.WriteAt(IF_ADDR, newVal)
+	gb.MMU.write(IF_ADDR, newVal)
 }
 
 func (gb *GB) resetIFFlag(idx uint8) {
-	existingVal := gb.MMU.ReadAt(IF_ADDR)
+	existingVal := gb.MMU.Peek(IF_ADDR)
 	newVal := common.ResetBitAtIndex(existingVal, idx)
-	gb.MMU.WriteAt(IF_ADDR, newVal)
+	gb.MMU.write(IF_ADDR, newVal)
 }
 
 func (gb *GB) executeInterrupt(idx uint8) {
 	// Disable further interrupts
 	gb.ResetIME()
 	gb.resetIFFlag(idx)
-	// Start handling the interrupt
-	gb.CPU.instrPushSPn16(gb.CPU.PC)
+	// Start handling the interrupt: two internal cycles (the second one
+	// in the push), pushing PC and loading the vector take 5 M-cycles.
+	pc := gb.CPU.PC
+	gb.CPU.idle()
+	gb.CPU.instrPushSPn16(pc)
 	switch idx {
 		case 0: gb.CPU.PC = 0x40
 		case 1: gb.CPU.PC = 0x48
@@ -58,21 +112,24 @@ func (gb *GB) executeInterrupt(idx uint8) {
 		case 3: gb.CPU.PC = 0x58
 		case 4: gb.CPU.PC = 0x60
 	}
+	gb.CPU.idle()
+	gb.CPU.pushFrame(Frame{Kind: FrameInterrupt, CallSite: pc, Target: gb.CPU.PC, ReturnAddr: pc, SP: gb.CPU.SP.value})
 }
 
 
 func (gb *GB) handleInterrupts() int {
-	if !gb.interruptsEnabled {
+	// A locked up CPU never services interrupts again.
+	if !gb.CPU.IME || gb.CPU.locked {
 		return 0
 	}
 	// Handle pending interrupts (if any based) on priority
-	requestedInterrupts := gb.MMU.ReadAt(IF_ADDR)
-	enabledInterrupts := gb.MMU.ReadAt(IE_ADDR)
+	requestedInterrupts := gb.MMU.Peek(IF_ADDR)
+	enabledInterrupts := gb.MMU.Peek(IE_ADDR)
 	cycles := 0
 	for i := uint8(0); i < 5; i++ {
 		if common.TestBitAtIndex(enabledInterrupts, i) && common.TestBitAtIndex(requestedInterrupts, i) {
 			gb.executeInterrupt(i)
-			cycles += 5
+			cycles += 20
 		}
 	}
 	return cycles
@@ -80,11 +137,11 @@ func (gb *GB) handleInterrupts() int {
 
 // Enable all interrupts globally
 func (gb *GB) SetIME() {
-	gb.interruptsEnabled = true
+	gb.CPU.IME = true
 }
 
 func (gb *GB) ResetIME() {
-	gb.interruptsEnabled = false
+	gb.CPU.IME = false
 }
 
 func (gb *GB) Init() error {
@@ -93,27 +150,82 @@ func (gb *GB) Init() error {
 	if err := gb.CPU.Init(gb); err != nil {
 		return fmt.Errorf("failed to initialize CPU: %v", err)
 	}
-	gb.MMU.Init(gb)
+	if err := gb.MMU.Init(gb); err != nil {
+		return fmt.Errorf("failed to initialize MMU: %v", err)
+	}
+	// Devices register their IO registers with the MMU.
+	gb.PPU.Init(gb)
+	gb.Timer.Init(gb)
+	gb.Serial.Init(gb)
+	gb.APU.Init(gb)
+	gb.Joypad.Init(gb)
+	if gb.skipBoot {
+		gb.applyPostBootState(gb.model)
+	}
 	gb.masterClk = time.NewTicker(time.Second / time.Duration(common.ClkFrequency))
-	gb.interruptsEnabled = false
+	gb.CPU.IME = false
+	return nil
+}
+
+// Advance everything but the CPU by the given number of clocks.
+func (gb *GB) tickDevices(cycles int) {
+	gb.PPU.Tick(uint8(cycles))
+	gb.Timer.Tick(cycles)
+	gb.Serial.Tick(cycles)
+}
+
+// Step executes a single instruction (and any interrupt dispatch following
+// it) and advances the rest of the system accordingly.
+func (gb *GB) Step() (int, error) {
+	pc := gb.CPU.PC
+	frame := gb.PPU.frame
+	elapsedCycles, err := gb.CPU.Tick()
+	if err != nil {
+		return elapsedCycles, err
+	}
+	if hit := gb.MMU.takeWatchHit(); hit != nil {
+		hit.PC = pc
+		err = hit
+	}
+	// Interrupt dispatch advances the devices as it goes, like instructions.
+	interruptCycles := gb.handleInterrupts()
+	totalCycles := elapsedCycles + interruptCycles
+	if gb.PPU.frame != frame && gb.rewind != nil {
+		gb.captureRewind()
+	}
+	// Accesses made while dispatching an interrupt are reported as well.
+	if hit := gb.MMU.takeWatchHit(); hit != nil && err == nil {
+		hit.PC = pc
+		err = hit
+	}
+	return totalCycles, err
+}
+
+// RunFrame steps until the PPU completes the current frame.
+func (gb *GB) RunFrame() error {
+	frame := gb.PPU.frame
+	for gb.PPU.frame == frame {
+		if _, err := gb.Step(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// Frame returns the number of frames completed since power on.
+func (gb *GB) Frame() uint64 {
+	return gb.PPU.frame
+}
+
 // Main emulation loop
 func (gb *GB) Emulate() error {
 	fmt.Println("Started emulation...")
-	totalCycles := 0
-	i := 0
 	for {
 		<- gb.masterClk.C
-		elapsedCycles := gb.CPU.Tick()
-		interruptCycles := gb.handleInterrupts()
-		totalCycles = elapsedCycles + interruptCycles
-		i += 1
 		// TODO: Handle synchronization between CPU and PPU
 		// we should render the screen at 60 fps
-		if totalCycles == 0 {
-			continue
+		if _, err := gb.Step(); err != nil {
+			return err
 		}
 	}
-}
\ No newline at end of file
+}
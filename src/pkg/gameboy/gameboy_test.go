@@ -0,0 +1,20 @@
+package gameboy
+
+import "testing"
+
+// Builds a machine running rom from 0x0100, past the boot ROM.
+func newTestGB(t *testing.T, rom []byte) *GB {
+	t.Helper()
+	gb, err := NewGBFromROM(rom)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return gb
+}
+
+// A 32 KiB ROM with code at 0x0100.
+func testROM(code ...byte) []byte {
+	rom := make([]byte, 0x8000)
+	copy(rom[0x100:], code)
+	return rom
+}
@@ -0,0 +1,91 @@
+package gameboy
+
+import (
+	"errors"
+	"fmt"
+)
+
+/*
+Opcodes 0xD3, 0xDB, 0xDD, 0xE3, 0xE4, 0xEB, 0xEC, 0xED, 0xF4, 0xFC and 0xFD
+are not part of the SM83 instruction set. Executing one of them hard-locks the
+CPU on real hardware: it stops fetching instructions and never services an
+interrupt again, while the PPU (and the rest of the system) keeps running.
+*/
+var illegalOpcodes = []byte{0xD3, 0xDB, 0xDD, 0xE3, 0xE4, 0xEB, 0xEC, 0xED, 0xF4, 0xFC, 0xFD}
+
+// IllegalOpcodePolicy decides how the emulator reacts to an illegal opcode.
+type IllegalOpcodePolicy uint8
+
+const (
+	// Emulate the hardware lock-up.
+	IllegalOpLock IllegalOpcodePolicy = iota
+	// Stop emulation and return an IllegalOpcodeError to the host.
+	IllegalOpError
+	// Stop emulation with an error wrapping ErrBreak so the debugger can take over.
+	IllegalOpBreak
+)
+
+// ErrBreak is wrapped by errors which should drop the host into the debugger.
+var ErrBreak = errors.New("break")
+
+// ErrUnimplementedOpcode is wrapped by the errors for opcodes the CPU doesn't
+// implement yet, along with ErrBreak.
+var ErrUnimplementedOpcode = errors.New("unimplemented opcode")
+
+type IllegalOpcodeError struct {
+	Opcode byte
+	Addr   uint16
+}
+
+func (e *IllegalOpcodeError) Error() string {
+	return fmt.Sprintf("illegal opcode %#02x at %#04x", e.Opcode, e.Addr)
+}
+
+func ParseIllegalOpcodePolicy(s string) (IllegalOpcodePolicy, error) {
+	switch s {
+	case "lock":
+		return IllegalOpLock, nil
+	case "error":
+		return IllegalOpError, nil
+	case "break":
+		return IllegalOpBreak, nil
+	}
+	return IllegalOpLock, fmt.Errorf("unknown illegal opcode policy %q (want lock, error or break)", s)
+}
+
+func (p IllegalOpcodePolicy) String() string {
+	switch p {
+	case IllegalOpError:
+		return "error"
+	case IllegalOpBreak:
+		return "break"
+	}
+	return "lock"
+}
+
+// Called by the instruction table once an illegal opcode has been fetched.
+func (cpu *CPU) illegalOpcode(opcode byte) {
+	// Leave PC on the offending opcode so it shows up in register dumps.
+	addr := cpu.PC - 1
+	switch cpu.IllegalOpcodePolicy {
+	case IllegalOpLock:
+		cpu.locked = true
+	case IllegalOpError:
+		cpu.PC = addr
+		cpu.fault = &IllegalOpcodeError{Opcode: opcode, Addr: addr}
+	case IllegalOpBreak:
+		cpu.PC = addr
+		cpu.fault = fmt.Errorf("%w: %w", ErrBreak, &IllegalOpcodeError{Opcode: opcode, Addr: addr})
+	}
+}
+
+// Installs the handlers of the illegal opcodes, over the ones the instruction
+// table gives to opcodes it doesn't implement.
+func installIllegalOpcodes() {
+	for _, opcode := range illegalOpcodes {
+		opcode := opcode
+		instructions[opcode] = func(cpu *CPU) {
+			cpu.illegalOpcode(opcode)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package gameboy
+
+import (
+	"errors"
+	"testing"
+)
+
+// Runs an illegal opcode at 0x0000 of a flat bus.
+func runIllegal(opcode byte, policy IllegalOpcodePolicy) (*CPU, *testBus, error) {
+	bus := &testBus{}
+	bus.mem[0] = opcode
+	cpu := NewCPU(bus, false)
+	cpu.Init(nil)
+	cpu.IllegalOpcodePolicy = policy
+	_, err := cpu.Tick()
+	return cpu, bus, err
+}
+
+func TestIllegalOpcodePolicies(t *testing.T) {
+	for _, opcode := range illegalOpcodes {
+		for _, policy := range []IllegalOpcodePolicy{IllegalOpLock, IllegalOpError, IllegalOpBreak} {
+			cpu, _, err := runIllegal(opcode, policy)
+			if errors.Is(err, ErrUnimplementedOpcode) {
+				t.Fatalf("%#02x: handled as unimplemented: %v", opcode, err)
+			}
+			var illegal *IllegalOpcodeError
+			switch policy {
+			case IllegalOpLock:
+				if err != nil || !cpu.locked {
+					t.Errorf("%#02x lock: err %v, locked %v", opcode, err, cpu.locked)
+				}
+			case IllegalOpError:
+				if !errors.As(err, &illegal) || errors.Is(err, ErrBreak) {
+					t.Errorf("%#02x error: got %v", opcode, err)
+				}
+			case IllegalOpBreak:
+				if !errors.As(err, &illegal) || !errors.Is(err, ErrBreak) {
+					t.Errorf("%#02x break: got %v", opcode, err)
+				}
+			}
+			if illegal != nil && (illegal.Opcode != opcode || illegal.Addr != 0 || cpu.PC != 0) {
+				t.Errorf("%#02x %v: error %+v with PC %#04x, want the opcode at 0", opcode, policy, illegal, cpu.PC)
+			}
+		}
+	}
+}
+
+func TestIllegalOpcodeLockKeepsTicking(t *testing.T) {
+	cpu, bus, _ := runIllegal(0xD3, IllegalOpLock)
+	bus.accesses = nil
+	for i := 0; i < 3; i++ {
+		clocks := bus.clocks
+		cycles, err := cpu.Tick()
+		if err != nil || cycles != 4 || bus.clocks != clocks + 4 {
+			t.Fatalf("tick %d: %d cycles, %d clocks on the bus, err %v", i, cycles, bus.clocks - clocks, err)
+		}
+	}
+	if cpu.PC != 1 || len(bus.accesses) != 0 {
+		t.Errorf("locked CPU at PC %#04x made accesses %v", cpu.PC, bus.accesses)
+	}
+}
+
+func TestIllegalOpcodeLockIgnoresInterrupts(t *testing.T) {
+	gb := newTestGB(t, testROM(0xFB, 0xD3))
+	if _, err := gb.Step(); err != nil {
+		t.Fatal(err)
+	}
+	gb.MMU.WriteAt(IE_ADDR, 0x01)
+	gb.RequestInterrupt(0)
+	for i := 0; i < 3; i++ {
+		if _, err := gb.Step(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !gb.CPU.locked || gb.CPU.PC != 0x0102 {
+		t.Errorf("locked %v at PC %#04x, want locked at 0x0102", gb.CPU.locked, gb.CPU.PC)
+	}
+	if gb.MMU.Peek(IF_ADDR) & 0x01 == 0 {
+		t.Error("the pending interrupt was serviced")
+	}
+}
+
+func TestParseIllegalOpcodePolicy(t *testing.T) {
+	for _, policy := range []IllegalOpcodePolicy{IllegalOpLock, IllegalOpError, IllegalOpBreak} {
+		got, err := ParseIllegalOpcodePolicy(policy.String())
+		if err != nil || got != policy {
+			t.Errorf("ParseIllegalOpcodePolicy(%q) = %v, %v", policy.String(), got, err)
+		}
+	}
+	if _, err := ParseIllegalOpcodePolicy("ignore"); err == nil {
+		t.Error("accepted an unknown policy")
+	}
+}
@@ -3,31 +3,9 @@ package gameboy
 import (
 	"fmt"
 	"gopherboy/pkg/common"
-	"log"
-	"os"
 	"strings"
 )
 
-// OpcodeCycles is the number of cpu cycles for each normal opcode.
-var OpcodeCycles = []int{
-	1, 3, 2, 2, 1, 1, 2, 1, 5, 2, 2, 2, 1, 1, 2, 1, // 0
-	0, 3, 2, 2, 1, 1, 2, 1, 3, 2, 2, 2, 1, 1, 2, 1, // 1
-	2, 3, 2, 2, 1, 1, 2, 1, 2, 2, 2, 2, 1, 1, 2, 1, // 2
-	2, 3, 2, 2, 3, 3, 3, 1, 2, 2, 2, 2, 1, 1, 2, 1, // 3
-	1, 1, 1, 1, 1, 1, 2, 1, 1, 1, 1, 1, 1, 1, 2, 1, // 4
-	1, 1, 1, 1, 1, 1, 2, 1, 1, 1, 1, 1, 1, 1, 2, 1, // 5
-	1, 1, 1, 1, 1, 1, 2, 1, 1, 1, 1, 1, 1, 1, 2, 1, // 6
-	2, 2, 2, 2, 2, 2, 0, 2, 1, 1, 1, 1, 1, 1, 2, 1, // 7
-	1, 1, 1, 1, 1, 1, 2, 1, 1, 1, 1, 1, 1, 1, 2, 1, // 8
-	1, 1, 1, 1, 1, 1, 2, 1, 1, 1, 1, 1, 1, 1, 2, 1, // 9
-	1, 1, 1, 1, 1, 1, 2, 1, 1, 1, 1, 1, 1, 1, 2, 1, // a
-	1, 1, 1, 1, 1, 1, 2, 1, 1, 1, 1, 1, 1, 1, 2, 1, // b
-	2, 3, 3, 4, 3, 4, 2, 4, 2, 4, 3, 0, 3, 6, 2, 4, // c
-	2, 3, 3, 0, 3, 4, 2, 4, 2, 4, 3, 0, 3, 0, 2, 4, // d
-	3, 3, 2, 0, 0, 4, 2, 4, 4, 1, 4, 0, 0, 0, 2, 4, // e
-	3, 3, 2, 1, 0, 4, 2, 4, 3, 2, 4, 1, 0, 0, 2, 4, // f
-} //0  1  2  3  4  5  6  7  8  9  a  b  c  d  e  f
-
 type InstrInfo struct {
 	opcode byte
 	addr uint16
@@ -53,35 +31,111 @@ func NewInstrInfo(opcode byte, addr uint16, instr string) *InstrInfo {
 	return instrInfo
 }
 
+// Fills in operand placeholders with their values, or with the label of the
+// address when a symbol file is loaded.
 func (ii *InstrInfo) updateInstrPlaceholder(op string, cpu *CPU) string {
 	newInstr := op
+	// Operands always follow the opcode.
+	operandAddr := ii.addr + 1
 	if strings.Contains(op, "a16") || strings.Contains(op, "n16") {
-		b1 := uint16(cpu.MMU.ReadAt(cpu.PC))
-		b2 := uint16(cpu.MMU.ReadAt(cpu.PC + 1))
+		b1 := uint16(cpu.Bus.Peek(operandAddr))
+		b2 := uint16(cpu.Bus.Peek(operandAddr + 1))
 		addr := b2 << 8 | b1
-		newInstr = strings.ReplaceAll(op, "a16", fmt.Sprintf("a16 {%#4x}", addr))
-		newInstr = strings.ReplaceAll(newInstr, "n16", fmt.Sprintf("n16 {%#4x}", addr))
+		a16, n16 := fmt.Sprintf("%#4x", addr), fmt.Sprintf("%#4x", addr)
+		if label, ok := cpu.gb.Label(addr); ok {
+			a16 = label
+		}
+		// Immediates may just be numbers, only exact matches are labelled.
+		if label, ok := cpu.gb.SymbolAt(addr); ok {
+			n16 = label
+		}
+		newInstr = strings.ReplaceAll(op, "a16", fmt.Sprintf("a16 {%s}", a16))
+		newInstr = strings.ReplaceAll(newInstr, "n16", fmt.Sprintf("n16 {%s}", n16))
 	}
 	if strings.Contains(op, "n8") || strings.Contains(op, "a8") {
-		addr := cpu.MMU.ReadAt(cpu.PC)
+		addr := cpu.Bus.Peek(operandAddr)
+		a8 := fmt.Sprintf("$FF00 + %#2x", addr)
+		if label, ok := cpu.gb.SymbolAt(0xFF00 + uint16(addr)); ok {
+			a8 = label
+		}
 		newInstr = strings.ReplaceAll(op, "n8", fmt.Sprintf("n8 {%#2x}", addr))
-		newInstr = strings.ReplaceAll(newInstr, "a8", fmt.Sprintf("a8 {$FF00 + %#2x}", addr))
+		newInstr = strings.ReplaceAll(newInstr, "a8", fmt.Sprintf("a8 {%s}", a8))
 	}
 	if strings.Contains(op, "e8") {
-		addr := int8(cpu.MMU.ReadAt(cpu.PC))
-		newInstr = strings.ReplaceAll(newInstr, "e8", fmt.Sprintf("e8 {%d}", addr))
+		offset := int8(cpu.Bus.Peek(operandAddr))
+		e8 := fmt.Sprintf("%d", offset)
+		// Relative to the end of the 2 byte instruction.
+		if label, ok := cpu.gb.Label(ii.addr + 2 + uint16(offset)); ok && ii.instrType == "JR" {
+			e8 = label
+		}
+		newInstr = strings.ReplaceAll(newInstr, "e8", fmt.Sprintf("e8 {%s}", e8))
 	}
 	return newInstr
 }
 
+// Len returns the size of the instruction in bytes, not counting a 0xCB prefix.
+func (ii *InstrInfo) Len() int {
+	switch {
+	case strings.Contains(ii.instr, "a16") || strings.Contains(ii.instr, "n16"):
+		return 3
+	case strings.Contains(ii.instr, "n8") || strings.Contains(ii.instr, "a8") || strings.Contains(ii.instr, "e8"):
+		return 2
+	}
+	return 1
+}
+
+// Opcodes which push a return address.
+var callOpcodes = map[byte]bool{
+	0xC4: true, 0xCC: true, 0xCD: true, 0xD4: true, 0xDC: true,
+	0xC7: true, 0xCF: true, 0xD7: true, 0xDF: true, 0xE7: true, 0xEF: true, 0xF7: true, 0xFF: true,
+}
+
+// Opcodes which pop a return address.
+var retOpcodes = map[byte]bool{
+	0xC0: true, 0xC8: true, 0xC9: true, 0xD0: true, 0xD8: true, 0xD9: true,
+}
+
+// IsCall reports whether opcode is a CALL or RST, which push a return address.
+func IsCall(opcode byte) bool {
+	return callOpcodes[opcode]
+}
+
+// IsReturn reports whether opcode is a RET or RETI.
+func IsReturn(opcode byte) bool {
+	return retOpcodes[opcode]
+}
+
+// DecodeAt decodes the instruction at addr, returning it together with the
+// address of the following instruction.
+func (cpu *CPU) DecodeAt(addr uint16) (*InstrInfo, uint16) {
+	opcode := cpu.Bus.Peek(addr)
+	if opcode == 0xCB {
+		addr++
+		opcode = cpu.Bus.Peek(addr)
+		return NewInstrInfo(opcode, addr, common.PrefixInstrDebugLookup[opcode]), addr + 1
+	}
+	ii := NewInstrInfo(opcode, addr, common.InstrDebugLookup[opcode])
+	return ii, addr + uint16(ii.Len())
+}
+
 func (ii *InstrInfo) DebugInfo(cpuState *CPU) string {
-	ii.leftOp = ii.updateInstrPlaceholder(ii.leftOp, cpuState)
-	ii.rightOp = ii.updateInstrPlaceholder(ii.rightOp, cpuState)
-	instrStr := ii.instrType + " " + ii.leftOp
-	if len(ii.rightOp) > 0 {
-		instrStr += "," + ii.rightOp
+	return fmt.Sprintf("%#4x %#2x\t%s\n", ii.addr, ii.opcode, ii.Text(cpuState))
+}
+
+// Text returns the instruction with its operand placeholders filled in.
+func (ii *InstrInfo) Text(cpuState *CPU) string {
+	leftOp := ii.updateInstrPlaceholder(ii.leftOp, cpuState)
+	rightOp := ii.updateInstrPlaceholder(ii.rightOp, cpuState)
+	instrStr := ii.instrType + " " + leftOp
+	if len(rightOp) > 0 {
+		instrStr += "," + rightOp
 	}
-	return fmt.Sprintf("%#4x %#2x\t%s\n", ii.addr, ii.opcode, instrStr)
+	return strings.TrimSpace(instrStr)
+}
+
+// Mnemonic returns the instruction name without its operands.
+func (ii *InstrInfo) Mnemonic() string {
+	return ii.instrType
 }
 
 // Implements common XOR operations and sets register value using the provided function.
@@ -124,7 +178,7 @@ func (cpu *CPU) instrDECr8(setFunc func(byte), val byte) {
 }
 
 func (cpu *CPU) instrLDn8(dest uint16, src byte) {
-	cpu.MMU.WriteAt(dest, src)
+	cpu.write(dest, src)
 }
 
 // Common function for LD r8,r8
@@ -146,6 +200,8 @@ func (cpu *CPU) instrJR(offset byte) {
 	signedOffset := int8(offset)
 	currAddr := int16(cpu.PC)
 	cpu.PC = uint16(currAddr + int16(signedOffset))
+	// Taken jumps spend a cycle loading PC.
+	cpu.idle()
 }
 
 // Push an 8-bit value onto the stack
@@ -156,18 +212,19 @@ func (cpu *CPU) instrPushSPn8(val byte) {
 
 // Push a 16-bit register onto the stack
 func (cpu *CPU) instrPushSPr16(r *Register) {
-	cpu.instrPushSPn8(r.Hi())
-	cpu.instrPushSPn8(r.Lo())
+	cpu.instrPushSPn16(r.Value())
 }
 
 // Pop a byte from stack
 func (cpu *CPU) instrPopSPn8() byte {
 	defer cpu.instrINCr16(&cpu.SP)
-	return cpu.MMU.ReadAt(cpu.SP.Value())
+	return cpu.read(cpu.SP.Value())
 }
 
 // Push a 16-bit value onto the stack
 func (cpu *CPU) instrPushSPn16(val uint16) {
+	// SP is decremented in a cycle of its own before the writes.
+	cpu.idle()
 	upperByte := byte(val >> 8)
 	lowerByte := byte(val & 0xFF)
 	cpu.instrPushSPn8(upperByte)
@@ -183,10 +240,14 @@ func (cpu *CPU) instrPopSPr16PC() {
 	valLo := cpu.instrPopSPn8()
 	valHi := cpu.instrPopSPn8()
 	cpu.PC = uint16(valHi) << 8 | uint16(valLo)
+	cpu.idle()
 }
 
 /* opcodes to function mappings */
 var instructions = [0x100]func(cpu *CPU) {
+	0x00: func(cpu *CPU) {
+		// NOP
+	},
 	/* LD */
 	0x01: func(cpu *CPU) {
 		// LD BC,n16
@@ -304,25 +365,30 @@ var instructions = [0x100]func(cpu *CPU) {
 	0xF0: func(cpu *CPU) {
 		// LD A, [0xFFOO + n8]
 		srcAddr := 0xFF00 + uint16(cpu.popPC8())
-		cpu.AF.SetHi(cpu.MMU.ReadAt(srcAddr))
+		cpu.AF.SetHi(cpu.read(srcAddr))
 	},
 	0xF2: func(cpu *CPU) {
 		// LD A, [0xFFOO + C]
 		srcAddr := 0xFF00 + uint16(cpu.BC.Lo())
-		cpu.AF.SetHi(cpu.MMU.ReadAt(srcAddr))
+		cpu.AF.SetHi(cpu.read(srcAddr))
 	},
-	/* Interrupt control (Ideally these should be delayed by 1 instruction) */
+	/* Interrupt control */
 	0xF3: func(cpu *CPU) {
 		// DI
-		cpu.gb.ResetIME()
+		cpu.IME = false
+		cpu.imePending = false
 	},
 	0xFB: func(cpu *CPU) {
-		// EI
-		cpu.gb.SetIME()
+		// EI, taking effect after the next instruction.
+		cpu.imePending = true
+	},
+	0x76: func(cpu *CPU) {
+		// HALT
+		cpu.halted = true
 	},
 	0xD9: func(cpu *CPU) {
 		// RETI
-		cpu.gb.SetIME()
+		cpu.IME = true
 		cpu.instrPopSPr16PC()
 	},
 	/* CP n8 */
@@ -335,23 +401,23 @@ var instructions = [0x100]func(cpu *CPU) {
 	0x0A: func(cpu *CPU) {
 		// LD A, [BC]
 		srcAddr := cpu.BC.Value()
-		cpu.AF.SetHi(cpu.MMU.ReadAt(srcAddr))
+		cpu.AF.SetHi(cpu.read(srcAddr))
 	},
 	0x1A: func(cpu *CPU) {
 		// LD A, [DE]
 		srcAddr := cpu.DE.Value()
-		cpu.AF.SetHi(cpu.MMU.ReadAt(srcAddr))
+		cpu.AF.SetHi(cpu.read(srcAddr))
 	},
 	0x2A: func(cpu *CPU) {
 		// LD A, [HL+]
 		srcAddr := cpu.HL.Value()
-		cpu.AF.SetHi(cpu.MMU.ReadAt(srcAddr))
+		cpu.AF.SetHi(cpu.read(srcAddr))
 		cpu.instrINCr16(&cpu.HL)
 	},
 	0x3A: func(cpu *CPU) {
 		// LD A, [HL-]
-		srcAddr := cpu.DE.Value()
-		cpu.AF.SetHi(cpu.MMU.ReadAt(srcAddr))
+		srcAddr := cpu.HL.Value()
+		cpu.AF.SetHi(cpu.read(srcAddr))
 		cpu.instrDECr16(&cpu.HL)
 	},
 	/* Jumps and Sub-routines */
@@ -390,7 +456,7 @@ var instructions = [0x100]func(cpu *CPU) {
 	0xFA: func(cpu *CPU) {
 		// LD A, [a16]
 		srcAddr := cpu.popPC16()
-		cpu.AF.SetHi(cpu.MMU.ReadAt(srcAddr))
+		cpu.AF.SetHi(cpu.read(srcAddr))
 	},
 	0xE1: func(cpu *CPU) {
 		// POP HL
@@ -417,9 +483,9 @@ var instructions = [0x100]func(cpu *CPU) {
 	},
 	0x34: func(cpu *CPU) {
 		// INC [HL]
-		val := cpu.MMU.ReadAt(cpu.HL.Value())
+		val := cpu.read(cpu.HL.Value())
 		newVal := val + 1
-		cpu.MMU.WriteAt(cpu.HL.Value(), newVal)
+		cpu.write(cpu.HL.Value(), newVal)
 
 		cpu.setZ(newVal == 0)
 		cpu.setN(false)
@@ -472,9 +538,9 @@ var instructions = [0x100]func(cpu *CPU) {
 	},
 	0x35: func(cpu *CPU) {
 		// DEC [HL]
-		val := cpu.MMU.ReadAt(cpu.HL.Value())
+		val := cpu.read(cpu.HL.Value())
 		newVal := val - 1
-		cpu.MMU.WriteAt(cpu.HL.Value(), newVal)
+		cpu.write(cpu.HL.Value(), newVal)
 
 		cpu.setZ(newVal == 0)
 		cpu.setN(true)
@@ -527,7 +593,7 @@ var instructions = [0x100]func(cpu *CPU) {
 	},
 	0xAE: func(cpu *CPU) {
 		// XOR [HL] 
-		val := cpu.MMU.ReadAt(cpu.HL.Value())
+		val := cpu.read(cpu.HL.Value())
 		cpu.instrXOR(val)
 	},
 	0xAF: func(cpu *CPU) {
@@ -590,7 +656,7 @@ func init() {
 		if i != 6 {
 			instructions[0x70 + i] = func(cpu *CPU) {
 				params := buildCbInstrParams(cpu, i)
-				cpu.instrLDr8(func (v byte) {cpu.MMU.WriteAt(cpu.HL.Value(), v)}, params.val)
+				cpu.instrLDr8(func (v byte) {cpu.write(cpu.HL.Value(), v)}, params.val)
 			}
 		}
 		instructions[0x78 + i] = func(cpu *CPU) {
@@ -606,10 +672,11 @@ func init() {
 	for k := range instructions {
 		if instructions[k] == nil {
 			instructions[k] = func(cpu *CPU) {
-				log.Printf("Unimplemented opcode: %#2x", k)
-				// TODO(abhi): replace this once debugger is implemented.
-				os.Exit(1)
+				// Leave PC on the opcode and hand over to the debugger.
+				cpu.PC--
+				cpu.fault = fmt.Errorf("%w: %w %#02x at %#04x", ErrBreak, ErrUnimplementedOpcode, k, cpu.PC)
 			}
 		}
 	}
+	installIllegalOpcodes()
 }
\ No newline at end of file
@@ -2,26 +2,6 @@ package gameboy
 
 import "gopherboy/pkg/common"
 
-// CBOpcodeCycles is the number of cpu cycles for each CB opcode.
-var CBOpcodeCycles = []int{
-	2, 2, 2, 2, 2, 2, 4, 2, 2, 2, 2, 2, 2, 2, 4, 2, // 0
-	2, 2, 2, 2, 2, 2, 4, 2, 2, 2, 2, 2, 2, 2, 4, 2, // 1
-	2, 2, 2, 2, 2, 2, 4, 2, 2, 2, 2, 2, 2, 2, 4, 2, // 2
-	2, 2, 2, 2, 2, 2, 4, 2, 2, 2, 2, 2, 2, 2, 4, 2, // 3
-	2, 2, 2, 2, 2, 2, 3, 2, 2, 2, 2, 2, 2, 2, 3, 2, // 4
-	2, 2, 2, 2, 2, 2, 3, 2, 2, 2, 2, 2, 2, 2, 3, 2, // 5
-	2, 2, 2, 2, 2, 2, 3, 2, 2, 2, 2, 2, 2, 2, 3, 2, // 6
-	2, 2, 2, 2, 2, 2, 3, 2, 2, 2, 2, 2, 2, 2, 3, 2, // 7
-	2, 2, 2, 2, 2, 2, 4, 2, 2, 2, 2, 2, 2, 2, 4, 2, // 8
-	2, 2, 2, 2, 2, 2, 4, 2, 2, 2, 2, 2, 2, 2, 4, 2, // 9
-	2, 2, 2, 2, 2, 2, 4, 2, 2, 2, 2, 2, 2, 2, 4, 2, // A
-	2, 2, 2, 2, 2, 2, 4, 2, 2, 2, 2, 2, 2, 2, 4, 2, // B
-	2, 2, 2, 2, 2, 2, 4, 2, 2, 2, 2, 2, 2, 2, 4, 2, // C
-	2, 2, 2, 2, 2, 2, 4, 2, 2, 2, 2, 2, 2, 2, 4, 2, // D
-	2, 2, 2, 2, 2, 2, 4, 2, 2, 2, 2, 2, 2, 2, 4, 2, // E
-	2, 2, 2, 2, 2, 2, 4, 2, 2, 2, 2, 2, 2, 2, 4, 2, // F
-} //0  1  2  3  4  5  6  7  8  9  a  b  c  d  e  f
-
 type cbInstrParams struct {
 	setter func(byte)
 	val byte
@@ -29,26 +9,27 @@ type cbInstrParams struct {
 
 var cbInstructions [0x100]func(cpu *CPU)
 
+// Operand i of an instruction, in the usual B, C, D, E, H, L, [HL], A order.
+// [HL] is only read when it is the operand, the read takes a cycle.
 func buildCbInstrParams(cpu *CPU, i int) cbInstrParams {
-	index := [8]cbInstrParams{
-		// B
-		0: {setter: cpu.BC.SetHi, val: cpu.BC.Hi()},
-		// C
-		1: {setter: cpu.BC.SetLo, val: cpu.BC.Lo()},
-		// D
-		2: {setter: cpu.DE.SetHi, val: cpu.DE.Hi()},
-		// E
-		3: {setter: cpu.DE.SetLo, val: cpu.DE.Lo()},
-		// H
-		4: {setter: cpu.HL.SetHi, val: cpu.HL.Hi()},
-		// L
-		5: {setter: cpu.HL.SetLo, val: cpu.HL.Lo()},
-		// [HL]
-		6: {setter: func(v byte) {cpu.MMU.WriteAt(cpu.HL.Value(), v)}, val: cpu.MMU.ReadAt(cpu.HL.Value())},
-		// A
-		7: {setter: cpu.AF.SetHi, val: cpu.AF.Hi()},
+	switch i {
+	// B
+	case 0: return cbInstrParams{setter: cpu.BC.SetHi, val: cpu.BC.Hi()}
+	// C
+	case 1: return cbInstrParams{setter: cpu.BC.SetLo, val: cpu.BC.Lo()}
+	// D
+	case 2: return cbInstrParams{setter: cpu.DE.SetHi, val: cpu.DE.Hi()}
+	// E
+	case 3: return cbInstrParams{setter: cpu.DE.SetLo, val: cpu.DE.Lo()}
+	// H
+	case 4: return cbInstrParams{setter: cpu.HL.SetHi, val: cpu.HL.Hi()}
+	// L
+	case 5: return cbInstrParams{setter: cpu.HL.SetLo, val: cpu.HL.Lo()}
+	// [HL]
+	case 6: return cbInstrParams{setter: func(v byte) {cpu.write(cpu.HL.Value(), v)}, val: cpu.read(cpu.HL.Value())}
 	}
-	return index[i]
+	// A
+	return cbInstrParams{setter: cpu.AF.SetHi, val: cpu.AF.Hi()}
 }
 
 func (cpu *CPU) instrRLC(setFunc func(byte), val byte) {
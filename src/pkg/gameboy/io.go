@@ -0,0 +1,114 @@
+package gameboy
+
+/*
+Memory mapped IO registers (0xFF00-0xFF7F and IE at 0xFFFF).
+
+Every device (PPU, timer, serial, APU ...) registers the registers it owns with
+the MMU during Init. Addresses without a registered register are unmapped:
+they read back as 0xFF and ignore writes.
+*/
+
+// IORegister is a single memory mapped IO register.
+type IORegister struct {
+	// Bits which hold state, all other bits read back as 1.
+	ReadMask byte
+	// Bits which can be changed by a CPU write.
+	WriteMask byte
+	// Optional device callbacks. OnRead replaces the stored value on CPU
+	// reads, OnWrite replaces the default (masked) store on CPU writes.
+	OnRead  func() byte
+	OnWrite func(val byte)
+
+	value byte
+}
+
+// Value returns the stored register value, without read masks applied.
+func (r *IORegister) Value() byte {
+	return r.value
+}
+
+// Set updates the stored value ignoring the write mask, used by devices
+// updating read-only bits.
+func (r *IORegister) Set(val byte) {
+	r.value = val
+}
+
+// Store updates the bits of the register that are writable by the CPU.
+func (r *IORegister) Store(val byte) {
+	r.value = (r.value &^ r.WriteMask) | (val & r.WriteMask)
+}
+
+func (r *IORegister) read() byte {
+	val := r.value
+	if r.OnRead != nil {
+		val = r.OnRead()
+	}
+	return val | ^r.ReadMask
+}
+
+func (r *IORegister) write(val byte) {
+	if r.OnWrite != nil {
+		r.OnWrite(val)
+		return
+	}
+	r.Store(val)
+}
+
+// RegisterIO maps reg at addr, replacing any previously registered register.
+func (mmu *MMU) RegisterIO(addr uint16, reg *IORegister) *IORegister {
+	if addr == IE_ADDR {
+		mmu.ie = reg
+	} else {
+		mmu.io[addr & 0x7F] = reg
+	}
+	return reg
+}
+
+// IORegisterAt returns the register mapped at addr, or nil if it is unmapped.
+func (mmu *MMU) IORegisterAt(addr uint16) *IORegister {
+	if addr == IE_ADDR {
+		return mmu.ie
+	}
+	if addr < 0xFF00 || addr >= 0xFF80 {
+		return nil
+	}
+	return mmu.io[addr & 0x7F]
+}
+
+func (mmu *MMU) readIO(addr uint16) byte {
+	reg := mmu.IORegisterAt(addr)
+	if reg == nil {
+		return 0xFF
+	}
+	return reg.read()
+}
+
+func (mmu *MMU) writeIO(addr uint16, val byte) {
+	reg := mmu.IORegisterAt(addr)
+	if reg == nil {
+		return
+	}
+	reg.write(val)
+}
+
+// Sets an IO register without going through the CPU write path.
+func (mmu *MMU) setIO(addr uint16, val byte) {
+	if reg := mmu.IORegisterAt(addr); reg != nil {
+		reg.Set(val)
+	}
+}
+
+// Registers owned by the MMU itself.
+func (mmu *MMU) registerIO() {
+	mmu.io = [0x80]*IORegister{}
+	mmu.RegisterIO(IF_ADDR, &IORegister{ReadMask: 0x1F, WriteMask: 0x1F})
+	mmu.RegisterIO(IE_ADDR, &IORegister{ReadMask: 0xFF, WriteMask: 0xFF})
+	bank := mmu.RegisterIO(BANK_ADDR, &IORegister{WriteMask: 0x01})
+	bank.OnWrite = func(val byte) {
+		// Any non-zero write unmaps the boot rom until the next reset.
+		if val != 0 {
+			mmu.biosEnabled = false
+			bank.Set(0x01)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package gameboy
+
+/*
+Joypad (P1 register).
+
+The eight buttons are wired as a 2x4 matrix: writing 0 to bit 4 selects the
+d-pad, writing 0 to bit 5 selects the action buttons, and the lower nibble
+reads the selected lines with pressed buttons as 0.
+*/
+
+const P1_ADDR = 0xFF00
+
+// Button bits used by SetButtons and movie files.
+const (
+	ButtonRight byte = 1 << iota
+	ButtonLeft
+	ButtonUp
+	ButtonDown
+	ButtonA
+	ButtonB
+	ButtonSelect
+	ButtonStart
+)
+
+type Joypad struct {
+	p1 *IORegister
+	// Currently pressed buttons, 1 = pressed.
+	buttons byte
+
+	gb *GB
+}
+
+func (j *Joypad) Init(gb *GB) {
+	j.gb = gb
+	j.buttons = 0
+	j.p1 = gb.MMU.RegisterIO(P1_ADDR, &IORegister{ReadMask: 0x3F, WriteMask: 0x30})
+	j.p1.OnRead = func() byte {
+		return j.p1.Value() & 0x30 | ^j.lines() & 0x0F
+	}
+}
+
+// Pressed buttons on the lines currently selected through P1, 1 = pressed.
+func (j *Joypad) lines() byte {
+	sel := j.p1.Value()
+	lines := byte(0)
+	if sel & 0x10 == 0 {
+		lines |= j.buttons & 0x0F
+	}
+	if sel & 0x20 == 0 {
+		lines |= j.buttons >> 4
+	}
+	return lines
+}
+
+// SetButtons updates the set of pressed buttons (a mask of Button* bits).
+func (j *Joypad) SetButtons(buttons byte) {
+	before := j.lines()
+	j.buttons = buttons
+	// The interrupt fires when a selected line goes from high to low.
+	if j.lines() &^ before != 0 {
+		j.gb.RequestInterrupt(INT_JOYPAD)
+	}
+}
+
+// Buttons returns the currently pressed buttons.
+func (j *Joypad) Buttons() byte {
+	return j.buttons
+}
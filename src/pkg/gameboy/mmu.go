@@ -5,9 +5,18 @@ import (
 	"os"
 )
 
+const (
+	BANK_ADDR = 0xFF50
+	// DMG boot roms are mapped at 0x0000-0x00FF.
+	dmgBootRomSize = 0x100
+	// CGB boot roms are additionally mapped at 0x0200-0x08FF.
+	cgbBootRomSize = 0x900
+)
+
 type MMU struct {
-	// 256 Bytes BIOS
-	bootRom [0x100]byte
+	// 256 Bytes DMG BIOS or 2304 Bytes CGB BIOS
+	bootRom [cgbBootRomSize]byte
+	bootRomSize int
 	// 16KiB ROM0
 	bank0 [0x4000]byte
 	// 16KiB ROM-N
@@ -20,39 +29,94 @@ type MMU struct {
 	wram [0x2000]byte
 	// 160 bytes OAM 
 	oam [0xA0]byte
-	// 128 Byte High RAM + IO mapped region
-	hram [0x100]byte
+	// 127 Byte High RAM
+	hram [0x7F]byte
+	// Memory mapped IO registers, 0xFF00-0xFF7F
+	io [0x80]*IORegister
+	// Interrupt enable register, 0xFFFF
+	ie *IORegister
+
+	// Let the CPU access VRAM and OAM regardless of the PPU mode.
+	DisableAccessBlocking bool
+
+	watchpoints []*Watchpoint
+	nextWatchID int
+	watchHit *WatchpointHit
 
 	gb *GB
 	biosEnabled bool
 	bootRomPath string
 	cartridgePath string
+	// Cartridge contents, read from cartridgePath when nil.
+	cartridge []byte
 }
 
 func (mmu *MMU) Init(gb *GB) error {
+	mmu.gb = gb
+	mmu.registerIO()
+	cartridge := mmu.cartridge
+	if cartridge == nil {
+		var err error
+		if cartridge, err = os.ReadFile(mmu.cartridgePath); err != nil {
+			return fmt.Errorf("could not read the cartridge, %v", err)
+		}
+	}
+	// TODO: Change this when bank switching is implemented
+	n := copy(mmu.bank0[:], cartridge)
+	if len(cartridge) > len(mmu.bank0) {
+		n += copy(mmu.bankN[:], cartridge[len(mmu.bank0):])
+	}
+	if gb.debug {
+		fmt.Printf("Copied cartridge into memory: %d bytes\n", n)
+	}
+	if gb.skipBoot {
+		mmu.biosEnabled = false
+		return nil
+	}
 	boot, err := os.ReadFile(mmu.bootRomPath)
 	if err != nil {
 		return fmt.Errorf("could not read the boot rom, %v", err)
 	}
-	_, err = os.ReadFile(mmu.cartridgePath)
-	if err != nil {
-		return fmt.Errorf("could not read the cartridge, %v", err)
+	if len(boot) != dmgBootRomSize && len(boot) != cgbBootRomSize {
+		return fmt.Errorf("boot rom has unexpected size %d, want %d (DMG) or %d (CGB) bytes", len(boot), dmgBootRomSize, cgbBootRomSize)
 	}
-	n := copy(mmu.bootRom[:], boot)
+	n = copy(mmu.bootRom[:], boot)
+	mmu.bootRomSize = n
 	fmt.Printf("Copied boot rom into memory: %d bytes\n", n)
 	mmu.biosEnabled = true
-	mmu.gb = gb
 	return nil
 }
 
+// ReadAt reads a byte on behalf of the CPU, checking watchpoints. VRAM and
+// OAM read 0xFF while the PPU uses them.
 func (mmu *MMU) ReadAt(addr uint16) byte {
+	val := mmu.Peek(addr)
+	if mmu.blocked(addr) {
+		val = 0xFF
+	}
+	if len(mmu.watchpoints) > 0 {
+		mmu.checkWatchpoints(addr, WatchRead, val, val)
+	}
+	return val
+}
+
+// BankAt returns the number of the bank mapped at addr, as used in RGBDS
+// symbol files. There is no memory bank controller yet, so ROMX is always
+// bank 1 and every other region has a single bank 0.
+func (mmu *MMU) BankAt(addr uint16) uint16 {
+	if addr >= 0x4000 && addr < 0x8000 {
+		return 1
+	}
+	return 0
+}
+
+// Peek reads a byte without triggering watchpoints or access blocking, for
+// debugging tools and DMA.
+func (mmu *MMU) Peek(addr uint16) byte {
 	index := addr & 0xF000
 	switch {
 	case index == 0x0: {
-		if mmu.gb.CPU.PC == 0x100 {
-			mmu.biosEnabled = false
-		}
-		if mmu.biosEnabled && addr < 0x100 {
+		if mmu.biosEnabled && mmu.isBootRomAddr(addr) {
 			return mmu.bootRom[addr]
 		}
 		return mmu.bank0[addr]
@@ -91,10 +155,9 @@ func (mmu *MMU) ReadAt(addr uint16) byte {
 			// IO and HRAM
 			case subIndex == 0xF00: {
 				// HRAM
-				if addr >= 0xFF80 {
+				if addr >= 0xFF80 && addr != IE_ADDR {
 					return mmu.hram[addr & 0x007F]
 				}
-				// TODO: Handle IO operations
 				return mmu.readIO(addr)
 			}
 		}
@@ -103,17 +166,68 @@ func (mmu *MMU) ReadAt(addr uint16) byte {
 	return 0
 }
 
-// TODO: Implement display IO handling
-func (mmu *MMU) readIO(addr uint16) byte {
-	switch {
-		// TODO: Handle various inputs (Joypad, MBC, LCD etc)
-		default:
-			return mmu.hram[addr - 0xFF00]
+// Whether addr is backed by the boot rom while it is mapped. The CGB boot rom
+// leaves a hole at 0x0100-0x01FF for the cartridge header.
+func (mmu *MMU) isBootRomAddr(addr uint16) bool {
+	if addr < dmgBootRomSize {
+		return true
+	}
+	return mmu.bootRomSize == cgbBootRomSize && addr >= 0x200 && addr < cgbBootRomSize
+}
+
+// Whether the PPU currently owns memory the CPU wants to access. Nothing is
+// blocked while the LCD is off.
+func (mmu *MMU) videoBusy() bool {
+	if mmu.DisableAccessBlocking {
+		return false
 	}
+	return mmu.gb.PPU.enabled()
+}
+
+// VRAM is inaccessible to the CPU while the PPU is drawing (mode 3).
+func (mmu *MMU) vramBlocked() bool {
+	return mmu.videoBusy() && mmu.gb.PPU.State == Draw
 }
 
+// OAM is inaccessible to the CPU during OAM search and drawing (modes 2 and 3).
+func (mmu *MMU) oamBlocked() bool {
+	state := mmu.gb.PPU.State
+	return mmu.videoBusy() && (state == OAMSearch || state == Draw)
+}
+
+// Whether the CPU can't access addr in the current PPU mode.
+func (mmu *MMU) blocked(addr uint16) bool {
+	switch {
+	case addr >= 0x8000 && addr < 0xA000:
+		return mmu.vramBlocked()
+	case addr >= 0xFE00 && addr < 0xFEA0:
+		return mmu.oamBlocked()
+	}
+	return false
+}
 
+// WriteAt writes a byte on behalf of the CPU, checking watchpoints. Writes to
+// VRAM and OAM are dropped while the PPU uses them.
 func (mmu *MMU) WriteAt(addr uint16, val byte) {
+	if len(mmu.watchpoints) == 0 {
+		if !mmu.blocked(addr) {
+			mmu.write(addr, val)
+		}
+		return
+	}
+	old := mmu.Peek(addr)
+	if !mmu.blocked(addr) {
+		mmu.write(addr, val)
+	}
+	mmu.checkWatchpoints(addr, WatchWrite, old, mmu.Peek(addr))
+}
+
+// Poke writes a byte without triggering watchpoints, for debugging tools.
+func (mmu *MMU) Poke(addr uint16, val byte) {
+	mmu.write(addr, val)
+}
+
+func (mmu *MMU) write(addr uint16, val byte) {
 	index := addr & 0xF000
 	switch {
 	case index < 0x8000: {
@@ -144,11 +258,9 @@ func (mmu *MMU) WriteAt(addr uint16, val byte) {
 			// IO and HRAM
 			case subIndex == 0xF00: {
 				// HRAM
-				if addr >= 0xFF80 {
-					// TODO: Maybe handle this differently?
+				if addr >= 0xFF80 && addr != IE_ADDR {
 					mmu.hram[addr & 0x007F] = val
 				} else {
-					// TODO: Handle IO operations
 					mmu.writeIO(addr, val)
 				}
 			}
@@ -157,13 +269,9 @@ func (mmu *MMU) WriteAt(addr uint16, val byte) {
 	}
 }
 
-// TODO: Implement display IO handling
-func (mmu *MMU) writeIO(addr uint16, val byte) {
-	switch {
-		// TODO: Handle various outputs (Joypad, MBC, LCD etc)
-		default:
-			mmu.hram[addr - 0xFF00] = val
-	}
+// The cartridge header's global checksum (0x014E-0x014F, big endian).
+func (mmu *MMU) globalChecksum() uint16 {
+	return uint16(mmu.bank0[0x014E]) << 8 | uint16(mmu.bank0[0x014F])
 }
 
 func NewMMU(bootRomPath, cartridgePath string) *MMU {
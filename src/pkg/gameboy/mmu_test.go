@@ -0,0 +1,43 @@
+package gameboy
+
+import "testing"
+
+func TestAccessBlocking(t *testing.T) {
+	gb := newTestGB(t, testROM())
+	mmu := gb.MMU
+	mmu.vram[0] = 0x12
+	mmu.oam[0] = 0x34
+	mmu.WriteAt(LCDC_ADDR, 0x80)
+
+	gb.PPU.State = Draw
+	if v := mmu.ReadAt(0x8000); v != 0xFF {
+		t.Errorf("VRAM read in mode 3 = %#02x, want 0xff", v)
+	}
+	if v := mmu.ReadAt(0xFE00); v != 0xFF {
+		t.Errorf("OAM read in mode 3 = %#02x, want 0xff", v)
+	}
+	mmu.WriteAt(0x8000, 0)
+	mmu.WriteAt(0xFE00, 0)
+	if mmu.vram[0] != 0x12 || mmu.oam[0] != 0x34 {
+		t.Errorf("writes in mode 3 weren't dropped: VRAM %#02x, OAM %#02x", mmu.vram[0], mmu.oam[0])
+	}
+	// OAM DMA isn't blocked.
+	mmu.WriteAt(DMA_ADDR, 0x80)
+	if mmu.oam[0] != 0x12 {
+		t.Errorf("OAM after DMA from VRAM in mode 3 = %#02x, want 0x12", mmu.oam[0])
+	}
+
+	gb.PPU.State = OAMSearch
+	if v := mmu.ReadAt(0x8000); v != 0x12 {
+		t.Errorf("VRAM read in mode 2 = %#02x, want 0x12", v)
+	}
+	if v := mmu.ReadAt(0xFE00); v != 0xFF {
+		t.Errorf("OAM read in mode 2 = %#02x, want 0xff", v)
+	}
+
+	mmu.DisableAccessBlocking = true
+	gb.PPU.State = Draw
+	if v := mmu.ReadAt(0xFE00); v != 0x12 {
+		t.Errorf("OAM read with blocking disabled = %#02x, want 0x12", v)
+	}
+}
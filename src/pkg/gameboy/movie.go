@@ -0,0 +1,254 @@
+package gameboy
+
+/*
+Input movies.
+
+A movie holds everything needed to replay a session bit-exactly: how the
+machine was started (power-on or an embedded save state), the RTC seed and
+the joypad state for every frame. Every checkpointInterval frames a hash of
+the machine state is stored so replays can detect the first frame at which
+emulation diverges from the recording.
+
+	header:  magic "GBMV" | version uint16 | ROM global checksum uint16 |
+	         start uint8 | model uint8 | skip boot uint8 | RTC seed uint64 |
+	         checkpoint interval uint32
+	start:   state length uint32 | save state   (only for movieStartState)
+	frames:  buttons uint8, followed by a state hash uint64 after every
+	         checkpoint interval frames
+
+All values are little endian. The hash is the FNV-1a hash of the save state
+of the machine rather than of the framebuffer: the PPU doesn't draw into the
+framebuffer yet, so its hash never changes, and the state covers everything
+the framebuffer will be drawn from.
+*/
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+)
+
+const (
+	movieMagic   = "GBMV"
+	movieVersion = 1
+	// Frames between two state checkpoints.
+	checkpointInterval = 60
+)
+
+const (
+	movieStartPowerOn uint8 = iota
+	movieStartState
+)
+
+type movieHeader struct {
+	Magic              [4]byte
+	Version            uint16
+	Checksum           uint16
+	Start              uint8
+	Model              uint8
+	SkipBoot           bool
+	RTCSeed            uint64
+	CheckpointInterval uint32
+}
+
+// DesyncError is returned when a replay no longer matches its recording.
+type DesyncError struct {
+	Frame    uint64
+	Expected uint64
+	Actual   uint64
+}
+
+func (e *DesyncError) Error() string {
+	return fmt.Sprintf("replay diverged at frame %d: state hash %#016x, recorded %#016x", e.Frame, e.Actual, e.Expected)
+}
+
+// Hash of the machine state, used for replay checkpoints.
+func (gb *GB) stateHash() (uint64, error) {
+	h := fnv.New64a()
+	if err := gb.SaveState(h); err != nil {
+		return 0, err
+	}
+	return h.Sum64(), nil
+}
+
+type MovieRecorder struct {
+	w *bufio.Writer
+	gb *GB
+	// Frames recorded so far.
+	frames uint64
+}
+
+// RecordMovie starts recording input to w. A power-on movie must be started
+// on a freshly initialised machine, otherwise the current state is embedded
+// in the movie. The RTC seed is stored for cartridges with a real time clock,
+// and used by gb from now on.
+func (gb *GB) RecordMovie(w io.Writer, rtcSeed uint64) (*MovieRecorder, error) {
+	header := movieHeader{
+		Version: movieVersion,
+		Checksum: gb.MMU.globalChecksum(),
+		Start: movieStartPowerOn,
+		Model: uint8(gb.model),
+		SkipBoot: gb.skipBoot,
+		RTCSeed: rtcSeed,
+		CheckpointInterval: checkpointInterval,
+	}
+	copy(header.Magic[:], movieMagic)
+	var state bytes.Buffer
+	if gb.cycles != 0 {
+		header.Start = movieStartState
+		if err := gb.SaveState(&state); err != nil {
+			return nil, err
+		}
+	}
+	bw := bufio.NewWriter(w)
+	if err := binary.Write(bw, binary.LittleEndian, &header); err != nil {
+		return nil, fmt.Errorf("failed to write movie header: %v", err)
+	}
+	if header.Start == movieStartState {
+		if err := binary.Write(bw, binary.LittleEndian, uint32(state.Len())); err != nil {
+			return nil, fmt.Errorf("failed to write movie start state: %v", err)
+		}
+		if _, err := bw.Write(state.Bytes()); err != nil {
+			return nil, fmt.Errorf("failed to write movie start state: %v", err)
+		}
+	}
+	gb.rtcSeed = rtcSeed
+	return &MovieRecorder{w: bw, gb: gb}, nil
+}
+
+// RunFrame runs one frame with the given buttons pressed and records it.
+func (r *MovieRecorder) RunFrame(buttons byte) error {
+	r.gb.Joypad.SetButtons(buttons)
+	if err := r.gb.RunFrame(); err != nil {
+		return err
+	}
+	if err := r.w.WriteByte(buttons); err != nil {
+		return fmt.Errorf("failed to write movie input: %v", err)
+	}
+	r.frames++
+	if r.frames % checkpointInterval == 0 {
+		hash, err := r.gb.stateHash()
+		if err != nil {
+			return err
+		}
+		if err := binary.Write(r.w, binary.LittleEndian, hash); err != nil {
+			return fmt.Errorf("failed to write movie checkpoint: %v", err)
+		}
+		// Keep at most a second of input in memory.
+		return r.w.Flush()
+	}
+	return nil
+}
+
+// Close flushes the remaining input. The underlying writer is not closed.
+func (r *MovieRecorder) Close() error {
+	return r.w.Flush()
+}
+
+type MoviePlayer struct {
+	r *bufio.Reader
+	gb *GB
+	interval uint64
+	// Frames replayed so far.
+	frames uint64
+}
+
+// PlayMovie prepares gb to replay a movie read from r. Power-on movies need a
+// freshly initialised machine with the same boot settings as the recording.
+func (gb *GB) PlayMovie(r io.Reader) (*MoviePlayer, error) {
+	br := bufio.NewReader(r)
+	var header movieHeader
+	if err := binary.Read(br, binary.LittleEndian, &header); err != nil {
+		return nil, fmt.Errorf("failed to read movie header: %v", err)
+	}
+	if string(header.Magic[:]) != movieMagic {
+		return nil, fmt.Errorf("not a movie file")
+	}
+	if header.Version > movieVersion {
+		return nil, fmt.Errorf("movie version %d is newer than supported version %d", header.Version, movieVersion)
+	}
+	if header.Checksum != gb.MMU.globalChecksum() {
+		return nil, fmt.Errorf("%w: checksum %#04x, cartridge has %#04x", ErrStateMismatch, header.Checksum, gb.MMU.globalChecksum())
+	}
+	switch header.Start {
+	case movieStartPowerOn:
+		if gb.cycles != 0 {
+			return nil, fmt.Errorf("power-on movies must be played on a freshly initialised machine")
+		}
+		if header.SkipBoot != gb.skipBoot || (gb.skipBoot && Model(header.Model) != gb.model) {
+			return nil, fmt.Errorf("movie was recorded with skip boot %v (model %v)", header.SkipBoot, Model(header.Model))
+		}
+	case movieStartState:
+		var size uint32
+		if err := binary.Read(br, binary.LittleEndian, &size); err != nil {
+			return nil, fmt.Errorf("failed to read movie start state: %v", err)
+		}
+		if err := gb.LoadState(io.LimitReader(br, int64(size))); err != nil {
+			return nil, fmt.Errorf("failed to load movie start state: %v", err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown movie start %d", header.Start)
+	}
+	if header.CheckpointInterval == 0 {
+		return nil, fmt.Errorf("movie has no checkpoint interval")
+	}
+	gb.rtcSeed = header.RTCSeed
+	return &MoviePlayer{r: br, gb: gb, interval: uint64(header.CheckpointInterval)}, nil
+}
+
+// Next replays a single frame. It returns io.EOF once the movie is over and
+// a *DesyncError if the frame doesn't match its checkpoint.
+func (p *MoviePlayer) Next() error {
+	buttons, err := p.r.ReadByte()
+	if err == io.EOF {
+		return io.EOF
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read movie input: %v", err)
+	}
+	p.gb.Joypad.SetButtons(buttons)
+	if err := p.gb.RunFrame(); err != nil {
+		return err
+	}
+	p.frames++
+	if p.frames % p.interval != 0 {
+		return nil
+	}
+	var expected uint64
+	if err := binary.Read(p.r, binary.LittleEndian, &expected); err != nil {
+		// The recording stopped right before its checkpoint.
+		if errors.Is(err, io.EOF) {
+			return io.EOF
+		}
+		return fmt.Errorf("failed to read movie checkpoint: %v", err)
+	}
+	actual, err := p.gb.stateHash()
+	if err != nil {
+		return err
+	}
+	if actual != expected {
+		return &DesyncError{Frame: p.frames, Expected: expected, Actual: actual}
+	}
+	return nil
+}
+
+// Verify replays the rest of the movie, returning the first divergence.
+func (p *MoviePlayer) Verify() error {
+	for {
+		err := p.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// Frames returns the number of frames replayed so far.
+func (p *MoviePlayer) Frames() uint64 {
+	return p.frames
+}
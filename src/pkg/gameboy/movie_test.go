@@ -0,0 +1,69 @@
+package gameboy
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// Records frames of rewindTestCode, which keeps reading the joypad.
+func recordTestMovie(t *testing.T, frames int) []byte {
+	t.Helper()
+	gb := newTestGB(t, testROM(rewindTestCode...))
+	var movie bytes.Buffer
+	rec, err := gb.RecordMovie(&movie, 1234)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < frames; i++ {
+		if err := rec.RunFrame(byte(i / 7) & 0x0F); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := rec.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return movie.Bytes()
+}
+
+func TestMovieReplay(t *testing.T) {
+	movie := recordTestMovie(t, 2 * checkpointInterval + 10)
+	gb := newTestGB(t, testROM(rewindTestCode...))
+	player, err := gb.PlayMovie(bytes.NewReader(movie))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := player.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if player.Frames() != 2 * checkpointInterval + 10 {
+		t.Errorf("replayed %d frames", player.Frames())
+	}
+	if gb.rtcSeed != 1234 {
+		t.Errorf("RTC seed %d, want 1234", gb.rtcSeed)
+	}
+}
+
+func TestMovieDesync(t *testing.T) {
+	movie := recordTestMovie(t, 2 * checkpointInterval)
+	for _, perturb := range []struct {
+		name string
+		fn   func(gb *GB)
+	}{
+		{"register", func(gb *GB) { gb.CPU.DE.Set(0xBEEF) }},
+		{"WRAM", func(gb *GB) { gb.MMU.WriteAt(0xD000, 0x55) }},
+	} {
+		gb := newTestGB(t, testROM(rewindTestCode...))
+		player, err := gb.PlayMovie(bytes.NewReader(movie))
+		if err != nil {
+			t.Fatal(err)
+		}
+		perturb.fn(gb)
+		var desync *DesyncError
+		if err := player.Verify(); !errors.As(err, &desync) {
+			t.Errorf("%s: got %v, want a desync", perturb.name, err)
+		} else if desync.Frame != checkpointInterval {
+			t.Errorf("%s: desync reported at frame %d, want %d", perturb.name, desync.Frame, checkpointInterval)
+		}
+	}
+}
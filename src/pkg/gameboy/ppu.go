@@ -20,15 +20,43 @@ const (
 )
 
 
+const (
+	LCDC_ADDR = 0xFF40
+	STAT_ADDR = 0xFF41
+	LY_ADDR   = 0xFF44
+	LYC_ADDR  = 0xFF45
+	DMA_ADDR  = 0xFF46
+)
+
+// Mode returns the mode number reported in the STAT register.
+func (s PPUState) Mode() byte {
+	switch s {
+	case Hblank:
+		return 0
+	case Vblank:
+		return 1
+	case OAMSearch:
+		return 2
+	}
+	return 3
+}
+
 type PPU struct {
-	
-	// TODO: Handle registers
+	lcdc *IORegister
+	stat *IORegister
+	lyc *IORegister
+	dma *IORegister
+
 	State PPUState
 	FrameBuffer [160*144]color.RGBA
 	// cycles in current scanline (move to next scanline after 456 cycles)
 	nCycles uint16
 	// currently rendering scanline, resets after 153 and enters VBlank
 	nScanline uint8
+	// frames completed since power on, incremented when entering VBlank
+	frame uint64
+	// LY always reads 0x90, for comparing against execution traces.
+	StubLY bool
 
 	gb *GB
 }
@@ -38,6 +66,45 @@ func (ppu *PPU) Init(gb *GB) {
 	ppu.nCycles = 0
 	ppu.State = OAMSearch
 	ppu.nScanline = 0
+	ppu.registerIO(gb.MMU)
+}
+
+func (ppu *PPU) registerIO(mmu *MMU) {
+	ppu.lcdc = mmu.RegisterIO(LCDC_ADDR, &IORegister{ReadMask: 0xFF, WriteMask: 0xFF})
+	ppu.stat = mmu.RegisterIO(STAT_ADDR, &IORegister{ReadMask: 0x7F, WriteMask: 0x78})
+	ppu.stat.OnRead = func() byte {
+		stat := ppu.stat.Value() & 0x78 | ppu.State.Mode()
+		if ppu.nScanline == ppu.lyc.Value() {
+			stat |= 0x04
+		}
+		return stat
+	}
+	ly := mmu.RegisterIO(LY_ADDR, &IORegister{ReadMask: 0xFF})
+	ly.OnRead = func() byte {
+		if ppu.StubLY {
+			return traceLY
+		}
+		return ppu.nScanline
+	}
+	ppu.lyc = mmu.RegisterIO(LYC_ADDR, &IORegister{ReadMask: 0xFF, WriteMask: 0xFF})
+	ppu.dma = mmu.RegisterIO(DMA_ADDR, &IORegister{ReadMask: 0xFF, WriteMask: 0xFF})
+	ppu.dma.OnWrite = func(val byte) {
+		ppu.dma.Store(val)
+		// TODO: OAM DMA takes 160 M-cycles on hardware, copy it at once for now.
+		src := uint16(val) << 8
+		for i := uint16(0); i < uint16(len(mmu.oam)); i++ {
+			mmu.oam[i] = mmu.Peek(src + i)
+		}
+	}
+	// SCY, SCX, BGP, OBP0, OBP1, WY, WX
+	for _, addr := range []uint16{0xFF42, 0xFF43, 0xFF47, 0xFF48, 0xFF49, 0xFF4A, 0xFF4B} {
+		mmu.RegisterIO(addr, &IORegister{ReadMask: 0xFF, WriteMask: 0xFF})
+	}
+}
+
+// Whether the LCD and PPU are switched on (LCDC bit 7).
+func (ppu *PPU) enabled() bool {
+	return ppu.lcdc.Value() & 0x80 != 0
 }
 
 func (ppu *PPU) Tick(cpuCycles uint8) {
@@ -67,6 +134,7 @@ func (ppu *PPU) Tick(cpuCycles uint8) {
 			// TODO: Implement Hblank logic here
 			if ppu.nScanline >= 144 {
 				ppu.State = Vblank
+				ppu.frame++
 				// TODO: Implement Vblank interrupt
 			} else {
 				ppu.State = OAMSearch
@@ -0,0 +1,178 @@
+package gameboy
+
+/*
+Rewind buffer.
+
+Every `interval` frames a save state is taken. Only the newest snapshot is
+kept as is; every older snapshot is stored as the XOR against the snapshot
+taken after it, deflated. Consecutive states differ in a few hundred bytes so
+the deltas compress extremely well, and since nothing depends on the oldest
+entry it can be dropped whenever the buffer goes over its memory budget.
+
+The joypad state of every frame is kept along with the snapshots, so that
+rewinding between two snapshots replays the input the frames had.
+*/
+import (
+	"bytes"
+	"compress/flate"
+	"errors"
+	"fmt"
+	"io"
+)
+
+var ErrNoRewindHistory = errors.New("no rewind history")
+
+type rewindEntry struct {
+	frame uint64
+	// Length of the snapshot before XOR-ing with the newer one.
+	size int
+	delta []byte
+	// Buttons of the frames up to the newer snapshot.
+	inputs []byte
+}
+
+type rewindBuffer struct {
+	interval uint64
+	// Maximum number of bytes held by the snapshots and inputs. The newest
+	// snapshot is always kept, even when it alone is over budget.
+	budget int
+	used int
+
+	latest []byte
+	latestFrame uint64
+	// Buttons of the frames since the newest snapshot.
+	inputs []byte
+	// Older snapshots, oldest first.
+	entries []rewindEntry
+	// Set while Rewind runs forward from a snapshot, which replays frames
+	// already in the buffer.
+	replaying bool
+}
+
+// EnableRewind starts taking a snapshot every interval frames, keeping at most
+// budget bytes of history. Passing an interval of 0 disables rewinding.
+func (gb *GB) EnableRewind(interval int, budget int) {
+	if interval <= 0 {
+		gb.rewind = nil
+		return
+	}
+	gb.rewind = &rewindBuffer{interval: uint64(interval), budget: budget}
+}
+
+// Rewind steps emulation back by the given number of frames. The closest
+// snapshot at or before the target frame is restored and emulation is run
+// forward from it. Rewinding further than the buffer holds stops at the
+// oldest snapshot.
+func (gb *GB) Rewind(frames int) error {
+	rb := gb.rewind
+	if rb == nil || rb.latest == nil {
+		return ErrNoRewindHistory
+	}
+	target := uint64(0)
+	if uint64(frames) < gb.PPU.frame {
+		target = gb.PPU.frame - uint64(frames)
+	}
+	for rb.latestFrame > target && len(rb.entries) > 0 {
+		if err := rb.pop(); err != nil {
+			return err
+		}
+	}
+	if err := gb.LoadState(bytes.NewReader(rb.latest)); err != nil {
+		return fmt.Errorf("failed to restore rewind snapshot: %v", err)
+	}
+	rb.replaying = true
+	defer func() { rb.replaying = false }()
+	replayed := 0
+	for gb.PPU.frame < target {
+		if replayed < len(rb.inputs) {
+			gb.Joypad.SetButtons(rb.inputs[replayed])
+		}
+		if err := gb.RunFrame(); err != nil {
+			return err
+		}
+		replayed++
+	}
+	// The input of the frames rewound over is gone.
+	rb.used -= len(rb.inputs) - replayed
+	rb.inputs = rb.inputs[:replayed]
+	return nil
+}
+
+// Called by Step after every completed frame.
+func (gb *GB) captureRewind() {
+	rb := gb.rewind
+	if rb.replaying {
+		return
+	}
+	if rb.latest != nil {
+		rb.inputs = append(rb.inputs, gb.Joypad.buttons)
+		rb.used++
+	}
+	if gb.PPU.frame % rb.interval != 0 {
+		return
+	}
+	var buf bytes.Buffer
+	if err := gb.SaveState(&buf); err != nil {
+		// Snapshots only go to memory, this can't fail in practice.
+		return
+	}
+	rb.push(buf.Bytes(), gb.PPU.frame)
+}
+
+func (rb *rewindBuffer) push(state []byte, frame uint64) {
+	if rb.latest != nil {
+		delta := deflate(xorBytes(rb.latest, state))
+		rb.entries = append(rb.entries, rewindEntry{frame: rb.latestFrame, size: len(rb.latest), delta: delta, inputs: rb.inputs})
+		rb.used += len(delta) - len(rb.latest)
+	}
+	rb.latest = state
+	rb.latestFrame = frame
+	rb.inputs = nil
+	rb.used += len(state)
+	for rb.used > rb.budget && len(rb.entries) > 0 {
+		rb.used -= len(rb.entries[0].delta) + len(rb.entries[0].inputs)
+		rb.entries = rb.entries[1:]
+	}
+}
+
+// Drops the newest snapshot, making the one before it the newest.
+func (rb *rewindBuffer) pop() error {
+	last := rb.entries[len(rb.entries) - 1]
+	delta, err := inflate(last.delta)
+	if err != nil {
+		return fmt.Errorf("corrupt rewind snapshot: %v", err)
+	}
+	rb.used -= len(rb.latest) + len(rb.inputs) + len(last.delta)
+	rb.latest = xorBytes(rb.latest, delta)[:last.size]
+	rb.latestFrame = last.frame
+	// The inputs of the entry are now those since the newest snapshot.
+	rb.inputs = last.inputs
+	rb.used += len(rb.latest)
+	rb.entries = rb.entries[:len(rb.entries) - 1]
+	return nil
+}
+
+// XOR of a and b, the shorter one is zero-padded.
+func xorBytes(a, b []byte) []byte {
+	if len(a) < len(b) {
+		a, b = b, a
+	}
+	out := make([]byte, len(a))
+	copy(out, a)
+	for i := range b {
+		out[i] ^= b[i]
+	}
+	return out
+}
+
+func deflate(data []byte) []byte {
+	var buf bytes.Buffer
+	w, _ := flate.NewWriter(&buf, flate.BestSpeed)
+	w.Write(data)
+	w.Close()
+	return buf.Bytes()
+}
+
+func inflate(data []byte) ([]byte, error) {
+	return io.ReadAll(flate.NewReader(bytes.NewReader(data)))
+}
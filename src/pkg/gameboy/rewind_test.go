@@ -0,0 +1,112 @@
+package gameboy
+
+import (
+	"bytes"
+	"testing"
+)
+
+// Bytes actually held by the buffer.
+func (rb *rewindBuffer) held() int {
+	n := len(rb.latest) + len(rb.inputs)
+	for _, e := range rb.entries {
+		n += len(e.delta) + len(e.inputs)
+	}
+	return n
+}
+
+func TestRewindBufferBudget(t *testing.T) {
+	rb := &rewindBuffer{interval: 1, budget: 1200}
+	state := make([]byte, 1000)
+	for frame := uint64(1); frame <= 20; frame++ {
+		// Changes of a few bytes, like consecutive save states.
+		state = append([]byte(nil), state...)
+		state[frame * 7] = byte(frame)
+		if rb.latest != nil {
+			rb.inputs = append(rb.inputs, byte(frame))
+			rb.used++
+		}
+		rb.push(state, frame)
+		if rb.used != rb.held() {
+			t.Fatalf("frame %d: accounted %d bytes, holding %d", frame, rb.used, rb.held())
+		}
+		if rb.used > rb.budget && len(rb.entries) > 0 {
+			t.Fatalf("frame %d: %d bytes over a budget of %d", frame, rb.used, rb.budget)
+		}
+	}
+	if len(rb.entries) == 0 || rb.entries[0].frame == 1 {
+		t.Errorf("%d entries, oldest at frame %d: nothing was evicted", len(rb.entries), rb.entries[0].frame)
+	}
+
+	// Popping restores the older snapshots exactly.
+	for len(rb.entries) > 0 {
+		want := rb.entries[len(rb.entries) - 1].frame
+		if err := rb.pop(); err != nil {
+			t.Fatal(err)
+		}
+		if rb.latestFrame != want || int(rb.latest[want * 7]) != int(byte(want)) || rb.latest[(want + 1) * 7] != 0 {
+			t.Fatalf("popped to frame %d, want %d", rb.latestFrame, want)
+		}
+		if rb.used != rb.held() {
+			t.Fatalf("accounted %d bytes after pop, holding %d", rb.used, rb.held())
+		}
+	}
+}
+
+func TestRewindBufferKeepsLatestOverBudget(t *testing.T) {
+	rb := &rewindBuffer{interval: 1, budget: 10}
+	rb.push(make([]byte, 100), 1)
+	rb.push(make([]byte, 100), 2)
+	if rb.latest == nil || rb.latestFrame != 2 || len(rb.entries) != 0 {
+		t.Errorf("latest frame %d with %d entries, want only the snapshot of frame 2", rb.latestFrame, len(rb.entries))
+	}
+}
+
+// Selects the d-pad and keeps copying P1 to $C000 while counting in $C001.
+var rewindTestCode = []byte{
+	0x3E, 0x20, // LD A,$20
+	0xE0, 0x00, // LDH [$00],A
+	0xF0, 0x00, // loop: LDH A,[$00]
+	0xEA, 0x00, 0xC0, // LD [$C000],A
+	0x21, 0x01, 0xC0, // LD HL,$C001
+	0x34, // INC [HL]
+	0x18, 0xF5, // JR loop
+}
+
+func TestRewindReplaysInput(t *testing.T) {
+	gb := newTestGB(t, testROM(rewindTestCode...))
+	gb.EnableRewind(4, 1 << 20)
+	states := map[uint64][]byte{}
+	for i := 0; i < 11; i++ {
+		gb.Joypad.SetButtons(byte(i) & 0x0F)
+		if err := gb.RunFrame(); err != nil {
+			t.Fatal(err)
+		}
+		states[gb.PPU.frame] = saveState(t, gb)
+	}
+	frame := gb.PPU.frame
+	if err := gb.Rewind(5); err != nil {
+		t.Fatal(err)
+	}
+	if gb.PPU.frame != frame - 5 {
+		t.Fatalf("rewound to frame %d, want %d", gb.PPU.frame, frame - 5)
+	}
+	if !bytes.Equal(saveState(t, gb), states[gb.PPU.frame]) {
+		t.Error("the rewound state differs from the one first emulated")
+	}
+	if gb.rewind.latestFrame > gb.PPU.frame {
+		t.Errorf("snapshot of frame %d kept after rewinding to frame %d", gb.rewind.latestFrame, gb.PPU.frame)
+	}
+	if gb.rewind.used != gb.rewind.held() {
+		t.Errorf("accounted %d bytes, holding %d", gb.rewind.used, gb.rewind.held())
+	}
+
+	// Emulation carries on recording from there.
+	for i := 0; i < 8; i++ {
+		if err := gb.RunFrame(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if gb.rewind.latestFrame != gb.PPU.frame - gb.PPU.frame % 4 {
+		t.Errorf("newest snapshot at frame %d on frame %d", gb.rewind.latestFrame, gb.PPU.frame)
+	}
+}
@@ -0,0 +1,345 @@
+package gameboy
+
+/*
+Save states.
+
+A save state is a small header followed by a list of tagged chunks, one per
+component:
+
+	header: magic "GBSS" | version uint16 | ROM global checksum uint16
+	chunk:  tag [4]byte  | length uint32  | payload
+	...
+	chunk:  "END " | 0
+
+All values are little endian. Payloads are fixed-size structs encoded with
+encoding/binary. To keep old states loadable, fields are only ever appended to
+a chunk struct (shorter payloads are zero-padded on load), new components get
+a new chunk, and unknown chunks are skipped. Bump stateVersion when the meaning
+of an existing field changes.
+*/
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image/color"
+	"io"
+)
+
+const (
+	stateMagic   = "GBSS"
+	stateVersion = 1
+	// Sanity limit for a single chunk, well above the largest one.
+	maxChunkSize = 1 << 20
+)
+
+type stateHeader struct {
+	Magic    [4]byte
+	Version  uint16
+	Checksum uint16
+}
+
+type cpuState struct {
+	AF, BC, DE, HL, SP, PC uint16
+	IME    bool
+	Locked bool
+	Halted bool
+	// EI executed, IME is set after the next instruction.
+	IMEPending bool
+}
+
+type mmuState struct {
+	VRAM        [0x2000]byte
+	ERAM        [0x2000]byte
+	WRAM        [0x2000]byte
+	OAM         [0xA0]byte
+	HRAM        [0x7F]byte
+	BiosEnabled bool
+}
+
+// Raw values of 0xFF00-0xFF7F followed by IE. Unmapped registers are zero.
+type ioState struct {
+	Registers [0x81]byte
+}
+
+type ppuState struct {
+	State       uint8
+	NCycles     uint16
+	NScanline   uint8
+	FrameBuffer [160 * 144]color.RGBA
+	Frame       uint64
+}
+
+type timerState struct {
+	Div uint16
+}
+
+type serialState struct {
+	TransferCycles int32
+}
+
+type joypadState struct {
+	Buttons byte
+}
+
+type sysState struct {
+	Cycles uint64
+}
+
+type stateChunk struct {
+	tag   string
+	state any
+}
+
+var ErrStateMismatch = errors.New("save state does not belong to the loaded cartridge")
+
+// SaveState serialises the whole machine to w.
+func (gb *GB) SaveState(w io.Writer) error {
+	header := stateHeader{Version: stateVersion, Checksum: gb.MMU.globalChecksum()}
+	copy(header.Magic[:], stateMagic)
+	if err := binary.Write(w, binary.LittleEndian, &header); err != nil {
+		return fmt.Errorf("failed to write save state header: %v", err)
+	}
+	cpu := gb.CPU.saveState()
+	mmu := gb.MMU.saveState()
+	ioRegs := gb.MMU.saveIOState()
+	ppu := gb.PPU.saveState()
+	timer := timerState{Div: gb.Timer.div}
+	serial := serialState{TransferCycles: int32(gb.Serial.transferCycles)}
+	joypad := joypadState{Buttons: gb.Joypad.buttons}
+	sys := sysState{Cycles: gb.cycles}
+	chunks := []stateChunk{
+		{"CPU ", &cpu},
+		{"MMU ", &mmu},
+		{"IO  ", &ioRegs},
+		{"PPU ", &ppu},
+		{"TIMR", &timer},
+		{"SERL", &serial},
+		{"JOYP", &joypad},
+		{"SYS ", &sys},
+	}
+	for _, c := range chunks {
+		if err := writeStateChunk(w, c.tag, c.state); err != nil {
+			return err
+		}
+	}
+	return writeStateChunk(w, "END ", nil)
+}
+
+// LoadState restores a machine saved with SaveState. The machine is left
+// untouched if the state can't be read.
+func (gb *GB) LoadState(r io.Reader) error {
+	var header stateHeader
+	if err := binary.Read(r, binary.LittleEndian, &header); err != nil {
+		return fmt.Errorf("failed to read save state header: %v", err)
+	}
+	if string(header.Magic[:]) != stateMagic {
+		return fmt.Errorf("not a save state")
+	}
+	if header.Version > stateVersion {
+		return fmt.Errorf("save state version %d is newer than supported version %d", header.Version, stateVersion)
+	}
+	if header.Checksum != gb.MMU.globalChecksum() {
+		return fmt.Errorf("%w: checksum %#04x, cartridge has %#04x", ErrStateMismatch, header.Checksum, gb.MMU.globalChecksum())
+	}
+
+	var (
+		cpu    cpuState
+		mmu    mmuState
+		ioRegs ioState
+		ppu    ppuState
+		timer  timerState
+		serial serialState
+		joypad joypadState
+		sys    sysState
+	)
+	chunks := map[string]any{
+		"CPU ": &cpu,
+		"MMU ": &mmu,
+		"IO  ": &ioRegs,
+		"PPU ": &ppu,
+		"TIMR": &timer,
+		"SERL": &serial,
+		"JOYP": &joypad,
+		"SYS ": &sys,
+	}
+	found := map[string]bool{}
+	for {
+		tag, payload, err := readStateChunk(r)
+		if err != nil {
+			return err
+		}
+		if tag == "END " {
+			break
+		}
+		state, ok := chunks[tag]
+		if !ok {
+			// Written by a newer version, skip it.
+			continue
+		}
+		if err := decodeStatePayload(payload, state); err != nil {
+			return fmt.Errorf("failed to decode %q chunk: %v", tag, err)
+		}
+		found[tag] = true
+	}
+
+	// Only apply components present in the state, older states keep the
+	// current values of components they don't know about.
+	if found["CPU "] {
+		gb.CPU.loadState(&cpu)
+	}
+	if found["MMU "] {
+		gb.MMU.loadState(&mmu)
+	}
+	if found["IO  "] {
+		gb.MMU.loadIOState(&ioRegs)
+	}
+	if found["PPU "] {
+		gb.PPU.loadState(&ppu)
+	}
+	if found["TIMR"] {
+		gb.Timer.div = timer.Div
+	}
+	if found["SERL"] {
+		gb.Serial.transferCycles = int(serial.TransferCycles)
+	}
+	if found["JOYP"] {
+		gb.Joypad.buttons = joypad.Buttons
+	}
+	if found["SYS "] {
+		gb.cycles = sys.Cycles
+	}
+	return nil
+}
+
+func writeStateChunk(w io.Writer, tag string, state any) error {
+	var payload bytes.Buffer
+	if state != nil {
+		if err := binary.Write(&payload, binary.LittleEndian, state); err != nil {
+			return fmt.Errorf("failed to encode %q chunk: %v", tag, err)
+		}
+	}
+	var hdr [8]byte
+	copy(hdr[:4], tag)
+	binary.LittleEndian.PutUint32(hdr[4:], uint32(payload.Len()))
+	if _, err := w.Write(hdr[:]); err != nil {
+		return fmt.Errorf("failed to write %q chunk: %v", tag, err)
+	}
+	if _, err := w.Write(payload.Bytes()); err != nil {
+		return fmt.Errorf("failed to write %q chunk: %v", tag, err)
+	}
+	return nil
+}
+
+func readStateChunk(r io.Reader) (string, []byte, error) {
+	var hdr [8]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return "", nil, fmt.Errorf("failed to read chunk header: %v", err)
+	}
+	tag := string(hdr[:4])
+	size := binary.LittleEndian.Uint32(hdr[4:])
+	if size > maxChunkSize {
+		return "", nil, fmt.Errorf("chunk %q is too large (%d bytes)", tag, size)
+	}
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return "", nil, fmt.Errorf("failed to read %q chunk: %v", tag, err)
+	}
+	return tag, payload, nil
+}
+
+// Decodes payload into state, zero-padding payloads written by older versions.
+func decodeStatePayload(payload []byte, state any) error {
+	if size := binary.Size(state); len(payload) < size {
+		payload = append(payload, make([]byte, size - len(payload))...)
+	}
+	return binary.Read(bytes.NewReader(payload), binary.LittleEndian, state)
+}
+
+func (cpu *CPU) saveState() cpuState {
+	return cpuState{
+		AF: cpu.AF.Value(),
+		BC: cpu.BC.Value(),
+		DE: cpu.DE.Value(),
+		HL: cpu.HL.Value(),
+		SP: cpu.SP.Value(),
+		PC: cpu.PC,
+		IME: cpu.IME,
+		Locked: cpu.locked,
+		Halted: cpu.halted,
+		IMEPending: cpu.imePending,
+	}
+}
+
+func (cpu *CPU) loadState(s *cpuState) {
+	cpu.AF.Set(s.AF)
+	cpu.BC.Set(s.BC)
+	cpu.DE.Set(s.DE)
+	cpu.HL.Set(s.HL)
+	cpu.SP.Set(s.SP)
+	cpu.PC = s.PC
+	cpu.IME = s.IME
+	cpu.locked = s.Locked
+	cpu.halted = s.Halted
+	cpu.imePending = s.IMEPending
+	// The calls leading to the restored state are unknown.
+	cpu.callStack = nil
+}
+
+func (mmu *MMU) saveState() mmuState {
+	return mmuState{
+		VRAM: mmu.vram,
+		ERAM: mmu.eram,
+		WRAM: mmu.wram,
+		OAM: mmu.oam,
+		HRAM: mmu.hram,
+		BiosEnabled: mmu.biosEnabled,
+	}
+}
+
+func (mmu *MMU) loadState(s *mmuState) {
+	mmu.vram = s.VRAM
+	mmu.eram = s.ERAM
+	mmu.wram = s.WRAM
+	mmu.oam = s.OAM
+	mmu.hram = s.HRAM
+	mmu.biosEnabled = s.BiosEnabled && mmu.bootRomSize > 0
+}
+
+func (mmu *MMU) saveIOState() ioState {
+	var s ioState
+	for i, reg := range mmu.io {
+		if reg != nil {
+			s.Registers[i] = reg.Value()
+		}
+	}
+	s.Registers[0x80] = mmu.ie.Value()
+	return s
+}
+
+func (mmu *MMU) loadIOState(s *ioState) {
+	for i, reg := range mmu.io {
+		if reg != nil {
+			reg.Set(s.Registers[i])
+		}
+	}
+	mmu.ie.Set(s.Registers[0x80])
+}
+
+func (ppu *PPU) saveState() ppuState {
+	return ppuState{
+		State: uint8(ppu.State),
+		NCycles: ppu.nCycles,
+		NScanline: ppu.nScanline,
+		FrameBuffer: ppu.FrameBuffer,
+		Frame: ppu.frame,
+	}
+}
+
+func (ppu *PPU) loadState(s *ppuState) {
+	ppu.State = PPUState(s.State)
+	ppu.nCycles = s.NCycles
+	ppu.nScanline = s.NScanline
+	ppu.FrameBuffer = s.FrameBuffer
+	ppu.frame = s.Frame
+}
@@ -0,0 +1,129 @@
+package gameboy
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+)
+
+// LD A,$42; LD [$C000],A; LD B,$12; EI; JR -2
+var stateTestCode = []byte{0x3E, 0x42, 0xEA, 0x00, 0xC0, 0x06, 0x12, 0xFB, 0x18, 0xFE}
+
+func stateTestGB(t *testing.T, checksum uint16) *GB {
+	rom := testROM(stateTestCode...)
+	rom[0x014E] = byte(checksum >> 8)
+	rom[0x014F] = byte(checksum)
+	return newTestGB(t, rom)
+}
+
+func saveState(t *testing.T, gb *GB) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := gb.SaveState(&buf); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestSaveStateRoundTrip(t *testing.T) {
+	gb := stateTestGB(t, 0x1234)
+	for i := 0; i < 100; i++ {
+		if _, err := gb.Step(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	gb.CPU.halted = true
+	gb.CPU.imePending = true
+	state := saveState(t, gb)
+
+	restored := stateTestGB(t, 0x1234)
+	if err := restored.LoadState(bytes.NewReader(state)); err != nil {
+		t.Fatal(err)
+	}
+	if again := saveState(t, restored); !bytes.Equal(again, state) {
+		t.Error("saving the restored machine gives a different state")
+	}
+	cpu := restored.CPU
+	if cpu.PC != gb.CPU.PC || cpu.AF.Hi() != 0x42 || cpu.BC.Hi() != 0x12 || !cpu.IME || !cpu.halted || !cpu.imePending {
+		t.Errorf("restored CPU: PC %#04x A %#02x B %#02x IME %v halted %v EI pending %v", cpu.PC, cpu.AF.Hi(), cpu.BC.Hi(), cpu.IME, cpu.halted, cpu.imePending)
+	}
+	if v := restored.MMU.Peek(0xC000); v != 0x42 {
+		t.Errorf("restored [$C000] = %#02x, want 0x42", v)
+	}
+	if restored.cycles != gb.cycles {
+		t.Errorf("restored %d cycles, want %d", restored.cycles, gb.cycles)
+	}
+}
+
+func TestLoadStateChecksumMismatch(t *testing.T) {
+	state := saveState(t, stateTestGB(t, 0x1234))
+	other := stateTestGB(t, 0x4321)
+	if err := other.LoadState(bytes.NewReader(state)); !errors.Is(err, ErrStateMismatch) {
+		t.Errorf("loading the state of another cartridge: got %v, want ErrStateMismatch", err)
+	}
+}
+
+func TestLoadStateNewerVersion(t *testing.T) {
+	gb := stateTestGB(t, 0x1234)
+	state := saveState(t, gb)
+	binary.LittleEndian.PutUint16(state[4:], stateVersion + 1)
+	gb.CPU.PC = 0x1000
+	if err := gb.LoadState(bytes.NewReader(state)); err == nil {
+		t.Error("loaded a state from a newer version")
+	}
+	if gb.CPU.PC != 0x1000 {
+		t.Error("the rejected state was applied")
+	}
+}
+
+func TestLoadStateSkipsUnknownChunks(t *testing.T) {
+	gb := stateTestGB(t, 0x1234)
+	gb.CPU.PC = 0x0150
+	state := saveState(t, gb)
+	// Insert a chunk before the end marker, as a newer version would.
+	var extra bytes.Buffer
+	extra.Write(state[:len(state) - 8])
+	if err := writeStateChunk(&extra, "XTRA", &[3]uint32{1, 2, 3}); err != nil {
+		t.Fatal(err)
+	}
+	extra.Write(state[len(state) - 8:])
+
+	restored := stateTestGB(t, 0x1234)
+	if err := restored.LoadState(&extra); err != nil {
+		t.Fatal(err)
+	}
+	if restored.CPU.PC != 0x0150 {
+		t.Errorf("PC = %#04x, want 0x0150", restored.CPU.PC)
+	}
+}
+
+func TestLoadStatePadsShortChunks(t *testing.T) {
+	gb := stateTestGB(t, 0x1234)
+	gb.CPU.halted = true
+	gb.CPU.imePending = true
+	// The CPU chunk before HALT and EI state were added.
+	old := struct {
+		AF, BC, DE, HL, SP, PC uint16
+		IME, Locked bool
+	}{AF: 0x1280, SP: 0xDFF0, PC: 0x0200, IME: true}
+	var state bytes.Buffer
+	header := stateHeader{Version: 1, Checksum: 0x1234}
+	copy(header.Magic[:], stateMagic)
+	binary.Write(&state, binary.LittleEndian, &header)
+	if err := writeStateChunk(&state, "CPU ", &old); err != nil {
+		t.Fatal(err)
+	}
+	writeStateChunk(&state, "END ", nil)
+
+	if err := gb.LoadState(&state); err != nil {
+		t.Fatal(err)
+	}
+	cpu := gb.CPU
+	if cpu.PC != 0x0200 || cpu.SP.Value() != 0xDFF0 || cpu.AF.Value() != 0x1280 || !cpu.IME {
+		t.Errorf("restored PC %#04x SP %#04x AF %#04x IME %v", cpu.PC, cpu.SP.Value(), cpu.AF.Value(), cpu.IME)
+	}
+	if cpu.halted || cpu.imePending {
+		t.Error("fields missing from the chunk weren't zeroed")
+	}
+}
@@ -0,0 +1,59 @@
+package gameboy
+
+/*
+Serial port (SB/SC).
+
+There is never a link partner connected: a transfer started with the internal
+clock shifts out SB over 8 bits (512 clocks each) and shifts in 1s. Every byte
+sent is written to Output, which is how most test ROMs report their results.
+*/
+import "io"
+
+const (
+	SB_ADDR = 0xFF01
+	SC_ADDR = 0xFF02
+
+	serialClocksPerByte = 512 * 8
+)
+
+type Serial struct {
+	sb *IORegister
+	sc *IORegister
+	// Remaining clocks of the current transfer, 0 when idle.
+	transferCycles int
+	// Receives every byte sent over the port, may be nil.
+	Output io.Writer
+
+	gb *GB
+}
+
+func (s *Serial) Init(gb *GB) {
+	s.gb = gb
+	s.transferCycles = 0
+	s.sb = gb.MMU.RegisterIO(SB_ADDR, &IORegister{ReadMask: 0xFF, WriteMask: 0xFF})
+	s.sc = gb.MMU.RegisterIO(SC_ADDR, &IORegister{ReadMask: 0x81, WriteMask: 0x81})
+	s.sc.OnWrite = func(val byte) {
+		s.sc.Store(val)
+		// Only transfers using the internal clock make progress without a partner.
+		if val & 0x81 == 0x81 {
+			s.transferCycles = serialClocksPerByte
+		}
+	}
+}
+
+func (s *Serial) Tick(cycles int) {
+	if s.transferCycles == 0 {
+		return
+	}
+	s.transferCycles -= cycles
+	if s.transferCycles > 0 {
+		return
+	}
+	s.transferCycles = 0
+	if s.Output != nil {
+		s.Output.Write([]byte{s.sb.Value()})
+	}
+	s.sb.Set(0xFF)
+	s.sc.Set(s.sc.Value() &^ 0x80)
+	s.gb.RequestInterrupt(INT_SERIAL)
+}
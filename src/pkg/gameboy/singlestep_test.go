@@ -0,0 +1,337 @@
+package gameboy
+
+/*
+The community SM83 single step tests (github.com/SingleStepTests/sm83), run
+against a flat 64 KiB bus. Each JSON file holds the tests of one opcode, with
+the register and RAM state before and after the instruction and the bus
+activity of every M-cycle. Copy the v1 directory under testdata/sm83:
+
+	testdata/sm83/v1/00.json
+	...
+	testdata/sm83/v1/cb ff.json
+
+Missing files are skipped, and so are opcodes the CPU doesn't implement yet.
+
+A few hand-written tests in the same format live in testdata/sm83/fixture and
+always run, so the harness is exercised without the community tests. They
+have the opcode at the initial PC and its fetch as the first cycle.
+*/
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+const (
+	sm83Dir        = "testdata/sm83/v1"
+	sm83FixtureDir = "testdata/sm83/fixture"
+)
+
+// Failures reported per opcode, the rest are only counted.
+const sm83MaxFailures = 5
+
+type sm83State struct {
+	PC  uint16 `json:"pc"`
+	SP  uint16 `json:"sp"`
+	A   byte   `json:"a"`
+	B   byte   `json:"b"`
+	C   byte   `json:"c"`
+	D   byte   `json:"d"`
+	E   byte   `json:"e"`
+	F   byte   `json:"f"`
+	H   byte   `json:"h"`
+	L   byte   `json:"l"`
+	IME byte   `json:"ime"`
+	// Missing from older versions of the tests.
+	IE  *byte  `json:"ie"`
+	RAM [][2]int `json:"ram"`
+}
+
+type sm83Test struct {
+	Name    string    `json:"name"`
+	Initial sm83State `json:"initial"`
+	Final   sm83State `json:"final"`
+	// [addr, value, "r-m"] for each M-cycle, with "-wm" for writes and "---"
+	// or null for cycles without bus activity.
+	Cycles []json.RawMessage `json:"cycles"`
+}
+
+// A memory access, in the order the CPU made them.
+type busAccess struct {
+	addr  uint16
+	val   byte
+	write bool
+}
+
+func (a busAccess) String() string {
+	if a.write {
+		return fmt.Sprintf("write %#02x to %#04x", a.val, a.addr)
+	}
+	return fmt.Sprintf("read %#02x from %#04x", a.val, a.addr)
+}
+
+// Flat RAM recording every access the CPU makes and the clocks it spends.
+type testBus struct {
+	mem      [0x10000]byte
+	accesses []busAccess
+	clocks   int
+}
+
+func (b *testBus) Read(addr uint16) byte {
+	b.accesses = append(b.accesses, busAccess{addr: addr, val: b.mem[addr]})
+	return b.mem[addr]
+}
+
+func (b *testBus) Write(addr uint16, val byte) {
+	b.accesses = append(b.accesses, busAccess{addr: addr, val: val, write: true})
+	b.mem[addr] = val
+}
+
+func (b *testBus) Peek(addr uint16) byte {
+	return b.mem[addr]
+}
+
+func (b *testBus) Tick(cycles int) {
+	b.clocks += cycles
+}
+
+// Accesses expected from the cycles of a test, leaving out idle cycles.
+func expectedAccesses(test *sm83Test) ([]busAccess, error) {
+	var accesses []busAccess
+	for _, raw := range test.Cycles {
+		var cycle []any
+		if err := json.Unmarshal(raw, &cycle); err != nil {
+			return nil, err
+		}
+		if len(cycle) != 3 {
+			continue
+		}
+		addr, aok := cycle[0].(float64)
+		val, vok := cycle[1].(float64)
+		kind, _ := cycle[2].(string)
+		if !aok || !vok || len(kind) < 2 {
+			continue
+		}
+		switch {
+		case kind[0] == 'r':
+			accesses = append(accesses, busAccess{addr: uint16(addr), val: byte(val)})
+		case kind[1] == 'w':
+			accesses = append(accesses, busAccess{addr: uint16(addr), val: byte(val), write: true})
+		}
+	}
+	return accesses, nil
+}
+
+func loadSM83Tests(path string) ([]sm83Test, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var tests []sm83Test
+	if err := json.Unmarshal(data, &tests); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return tests, nil
+}
+
+// Whether the tests of a file start with the opcode already fetched, PC
+// pointing past it. The tests were generated that way and end with the fetch
+// of the next opcode.
+func prefetched(tests []sm83Test, opcode byte) bool {
+	for i := range tests {
+		found := false
+		for _, entry := range tests[i].Initial.RAM {
+			if uint16(entry[0]) == tests[i].Initial.PC && byte(entry[1]) == opcode {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return true
+		}
+	}
+	return false
+}
+
+// Sets up the CPU and bus for a test.
+func (s *sm83State) load(cpu *CPU, bus *testBus, prefetch bool) {
+	bus.mem = [0x10000]byte{}
+	for _, entry := range s.RAM {
+		bus.mem[uint16(entry[0])] = byte(entry[1])
+	}
+	if s.IE != nil {
+		bus.mem[0xFFFF] = *s.IE
+	}
+	cpu.AF.Set(uint16(s.A) << 8 | uint16(s.F))
+	cpu.BC.Set(uint16(s.B) << 8 | uint16(s.C))
+	cpu.DE.Set(uint16(s.D) << 8 | uint16(s.E))
+	cpu.HL.Set(uint16(s.H) << 8 | uint16(s.L))
+	cpu.SP.Set(s.SP)
+	cpu.PC = s.PC
+	if prefetch {
+		cpu.PC--
+	}
+	cpu.IME = s.IME != 0
+	cpu.locked = false
+	cpu.halted = false
+	cpu.imePending = false
+	cpu.callStack = nil
+	bus.accesses = nil
+	bus.clocks = 0
+}
+
+// Compares the machine with the final state of a test, returning the
+// differences.
+func (s *sm83State) diff(cpu *CPU, bus *testBus, prefetch bool) []string {
+	var diffs []string
+	check := func(name string, got, want uint16) {
+		if got != want {
+			diffs = append(diffs, fmt.Sprintf("%s = %#04x, want %#04x", name, got, want))
+		}
+	}
+	pc := cpu.PC
+	if prefetch {
+		pc++
+	}
+	check("PC", pc, s.PC)
+	check("SP", cpu.SP.Value(), s.SP)
+	check("A", uint16(cpu.AF.Hi()), uint16(s.A))
+	check("F", uint16(cpu.AF.Lo()), uint16(s.F))
+	check("B", uint16(cpu.BC.Hi()), uint16(s.B))
+	check("C", uint16(cpu.BC.Lo()), uint16(s.C))
+	check("D", uint16(cpu.DE.Hi()), uint16(s.D))
+	check("E", uint16(cpu.DE.Lo()), uint16(s.E))
+	check("H", uint16(cpu.HL.Hi()), uint16(s.H))
+	check("L", uint16(cpu.HL.Lo()), uint16(s.L))
+	ime := uint16(0)
+	if cpu.IME {
+		ime = 1
+	}
+	check("IME", ime, uint16(s.IME))
+	if s.IE != nil {
+		check("IE", uint16(bus.mem[0xFFFF]), uint16(*s.IE))
+	}
+	for _, entry := range s.RAM {
+		addr := uint16(entry[0])
+		check(fmt.Sprintf("[%#04x]", addr), uint16(bus.mem[addr]), uint16(byte(entry[1])))
+	}
+	return diffs
+}
+
+// Runs a single test, returning what went wrong.
+func runSM83(cpu *CPU, bus *testBus, test *sm83Test, prefetch bool) ([]string, error) {
+	test.Initial.load(cpu, bus, prefetch)
+	if _, err := cpu.Tick(); err != nil {
+		return nil, err
+	}
+	diffs := test.Final.diff(cpu, bus, prefetch)
+	if bus.clocks / 4 != len(test.Cycles) {
+		diffs = append(diffs, fmt.Sprintf("took %d M-cycles, want %d", bus.clocks / 4, len(test.Cycles)))
+	}
+	want, err := expectedAccesses(test)
+	if err != nil {
+		return nil, err
+	}
+	got := bus.accesses
+	if prefetch {
+		// The opcode fetch belongs to the previous instruction in the tests,
+		// and the fetch of the next opcode to this one.
+		if len(got) > 0 {
+			got = got[1:]
+		}
+		if n := len(want); n > 0 && !want[n - 1].write && want[n - 1].addr == cpu.PC {
+			want = want[:n - 1]
+		}
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		diffs = append(diffs, fmt.Sprintf("bus accesses %v, want %v", got, want))
+	}
+	return diffs, nil
+}
+
+// Runs the tests of an opcode file. Unimplemented opcodes are skipped unless
+// required.
+func runSM83File(t *testing.T, cpu *CPU, bus *testBus, path string, first byte, required bool) {
+	tests, err := loadSM83Tests(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prefetch := prefetched(tests, first)
+	failures := 0
+	for i := range tests {
+		diffs, err := runSM83(cpu, bus, &tests[i], prefetch)
+		if errors.Is(err, ErrUnimplementedOpcode) && !required {
+			t.Skip(err)
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", tests[i].Name, err)
+		}
+		if len(diffs) == 0 {
+			continue
+		}
+		if failures < sm83MaxFailures {
+			t.Errorf("%s:\n\t%s", tests[i].Name, strings.Join(diffs, "\n\t"))
+		}
+		failures++
+	}
+	if failures > sm83MaxFailures {
+		t.Errorf("%d of %d tests failed", failures, len(tests))
+	}
+}
+
+func TestSingleStep(t *testing.T) {
+	if _, err := os.Stat(sm83Dir); err != nil {
+		t.Skipf("%s not found", sm83Dir)
+	}
+	bus := &testBus{}
+	cpu := NewCPU(bus, false)
+	cpu.Init(nil)
+	for _, prefix := range []string{"", "cb "} {
+		for op := 0; op < 0x100; op++ {
+			name := fmt.Sprintf("%s%02x", prefix, op)
+			t.Run(name, func(t *testing.T) {
+				path := filepath.Join(sm83Dir, name + ".json")
+				if _, err := os.Stat(path); err != nil {
+					t.Skipf("%s not found", path)
+				}
+				first := byte(op)
+				if prefix != "" {
+					first = 0xCB
+				}
+				runSM83File(t, cpu, bus, path, first, false)
+			})
+		}
+	}
+}
+
+func TestSingleStepFixture(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join(sm83FixtureDir, "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) == 0 {
+		t.Fatalf("no tests in %s", sm83FixtureDir)
+	}
+	bus := &testBus{}
+	cpu := NewCPU(bus, false)
+	cpu.Init(nil)
+	for _, path := range paths {
+		name := strings.TrimSuffix(filepath.Base(path), ".json")
+		t.Run(name, func(t *testing.T) {
+			op, err := strconv.ParseUint(strings.TrimPrefix(name, "cb "), 16, 8)
+			if err != nil {
+				t.Fatalf("file name %q isn't an opcode", name)
+			}
+			first := byte(op)
+			if strings.HasPrefix(name, "cb ") {
+				first = 0xCB
+			}
+			runSM83File(t, cpu, bus, path, first, true)
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package gameboy
+
+import (
+	"fmt"
+	"gopherboy/pkg/symbols"
+)
+
+// LoadSymbols reads an RGBDS .sym or .map file, whose labels are then used
+// wherever debug output shows an address.
+func (gb *GB) LoadSymbols(path string) error {
+	syms, err := symbols.Load(path)
+	if err != nil {
+		return err
+	}
+	gb.Symbols = syms
+	return nil
+}
+
+// SymbolAt returns the label defined exactly at addr in the bank currently
+// mapped there.
+func (gb *GB) SymbolAt(addr uint16) (string, bool) {
+	if gb == nil {
+		return "", false
+	}
+	return gb.Symbols.Name(gb.MMU.BankAt(addr), addr)
+}
+
+// Label returns addr relative to the closest label before it, e.g.
+// "Main+$3".
+func (gb *GB) Label(addr uint16) (string, bool) {
+	if gb == nil || gb.Symbols == nil {
+		return "", false
+	}
+	if _, _, ok := gb.Symbols.Nearest(gb.MMU.BankAt(addr), addr); !ok {
+		return "", false
+	}
+	return gb.Symbols.Format(gb.MMU.BankAt(addr), addr), true
+}
+
+// FormatAddr formats addr followed by its label, if any: "0x0153 <Main+$3>".
+func (gb *GB) FormatAddr(addr uint16) string {
+	if label, ok := gb.Label(addr); ok {
+		return fmt.Sprintf("%#04x <%s>", addr, label)
+	}
+	return fmt.Sprintf("%#04x", addr)
+}
@@ -0,0 +1,88 @@
+package gameboy
+
+/*
+DIV/TIMA/TMA/TAC timer.
+
+DIV is the upper byte of a free running 16-bit counter incremented every clock.
+TIMA is incremented on the falling edge of the counter bit selected by TAC
+(bit 9, 3, 5 or 7), which is also what makes writes to DIV and TAC able to
+increment TIMA.
+*/
+
+const (
+	DIV_ADDR  = 0xFF04
+	TIMA_ADDR = 0xFF05
+	TMA_ADDR  = 0xFF06
+	TAC_ADDR  = 0xFF07
+)
+
+// Counter bit observed for each TAC clock select value.
+var timerBits = [4]uint8{9, 3, 5, 7}
+
+type Timer struct {
+	div  uint16
+	tima *IORegister
+	tma  *IORegister
+	tac  *IORegister
+
+	gb *GB
+}
+
+func (t *Timer) Init(gb *GB) {
+	t.gb = gb
+	t.div = 0
+	mmu := gb.MMU
+	div := mmu.RegisterIO(DIV_ADDR, &IORegister{ReadMask: 0xFF, WriteMask: 0xFF})
+	div.OnRead = func() byte {
+		return byte(t.div >> 8)
+	}
+	div.OnWrite = func(byte) {
+		// Any write resets the whole counter.
+		t.setDiv(0)
+	}
+	t.tima = mmu.RegisterIO(TIMA_ADDR, &IORegister{ReadMask: 0xFF, WriteMask: 0xFF})
+	t.tma = mmu.RegisterIO(TMA_ADDR, &IORegister{ReadMask: 0xFF, WriteMask: 0xFF})
+	t.tac = mmu.RegisterIO(TAC_ADDR, &IORegister{ReadMask: 0x07, WriteMask: 0x07})
+	t.tac.OnWrite = func(val byte) {
+		before := t.signal()
+		t.tac.Store(val)
+		t.checkFallingEdge(before)
+	}
+}
+
+// State of the counter bit selected by TAC, ANDed with the timer enable bit.
+func (t *Timer) signal() bool {
+	tac := t.tac.Value()
+	if tac & 0x4 == 0 {
+		return false
+	}
+	return (t.div >> timerBits[tac & 0x3]) & 1 == 1
+}
+
+func (t *Timer) checkFallingEdge(before bool) {
+	if before && !t.signal() {
+		t.incrementTIMA()
+	}
+}
+
+func (t *Timer) incrementTIMA() {
+	tima := t.tima.Value() + 1
+	if tima == 0 {
+		tima = t.tma.Value()
+		t.gb.RequestInterrupt(INT_TIMER)
+	}
+	t.tima.Set(tima)
+}
+
+func (t *Timer) setDiv(val uint16) {
+	before := t.signal()
+	t.div = val
+	t.checkFallingEdge(before)
+}
+
+// Advance the timer by the given number of clocks.
+func (t *Timer) Tick(cycles int) {
+	for i := 0; i < cycles; i++ {
+		t.setDiv(t.div + 1)
+	}
+}
@@ -0,0 +1,229 @@
+package gameboy
+
+/*
+Execution traces, one entry per instruction with the state before it
+executes. The text format is the one of Gameboy Doctor:
+
+	A:01 F:B0 B:00 C:13 D:00 E:D8 H:01 L:4D SP:FFFE PC:0100 PCMEM:00,C3,13,02
+
+PCMEM holds the 4 bytes starting at PC. The binary format stores the same
+fields as fixed 16 byte records: A F B C D E H L, SP and PC little endian,
+then PCMEM.
+
+Reference logs are taken with LY stubbed to 0x90 so PPU timing can't make
+them diverge, LY reads return the same while tracing. Traces start at the
+cartridge entry point, use SkipBoot to get the post-boot state the reference
+logs begin with.
+*/
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Value LY reads as while tracing.
+const traceLY = 0x90
+
+const traceRecordSize = 16
+
+type TraceFormat uint8
+
+const (
+	TraceDoctor TraceFormat = iota
+	TraceBinary
+)
+
+func ParseTraceFormat(s string) (TraceFormat, error) {
+	switch strings.ToLower(s) {
+	case "doctor":
+		return TraceDoctor, nil
+	case "binary":
+		return TraceBinary, nil
+	}
+	return 0, fmt.Errorf("unknown trace format %q, expected doctor or binary", s)
+}
+
+type TraceEntry struct {
+	A, F, B, C, D, E, H, L byte
+	SP, PC uint16
+	PCMem [4]byte
+}
+
+func (e TraceEntry) String() string {
+	return fmt.Sprintf(
+		"A:%02X F:%02X B:%02X C:%02X D:%02X E:%02X H:%02X L:%02X SP:%04X PC:%04X PCMEM:%02X,%02X,%02X,%02X",
+		e.A, e.F, e.B, e.C, e.D, e.E, e.H, e.L, e.SP, e.PC,
+		e.PCMem[0], e.PCMem[1], e.PCMem[2], e.PCMem[3],
+	)
+}
+
+// ParseTraceEntry parses a Gameboy Doctor line.
+func ParseTraceEntry(line string) (TraceEntry, error) {
+	var e TraceEntry
+	fields := strings.Fields(line)
+	if len(fields) != 11 {
+		return e, fmt.Errorf("expected 11 fields in trace line %q", line)
+	}
+	regs := []*byte{&e.A, &e.F, &e.B, &e.C, &e.D, &e.E, &e.H, &e.L}
+	names := []string{"A", "F", "B", "C", "D", "E", "H", "L", "SP", "PC", "PCMEM"}
+	for i, field := range fields {
+		name, val, ok := strings.Cut(field, ":")
+		if !ok || name != names[i] {
+			return e, fmt.Errorf("expected %s in trace line %q", names[i], line)
+		}
+		switch {
+		case i < len(regs):
+			v, err := strconv.ParseUint(val, 16, 8)
+			if err != nil {
+				return e, fmt.Errorf("invalid %s in trace line %q", name, line)
+			}
+			*regs[i] = byte(v)
+		case name == "SP" || name == "PC":
+			v, err := strconv.ParseUint(val, 16, 16)
+			if err != nil {
+				return e, fmt.Errorf("invalid %s in trace line %q", name, line)
+			}
+			if name == "SP" {
+				e.SP = uint16(v)
+			} else {
+				e.PC = uint16(v)
+			}
+		default:
+			bytes := strings.Split(val, ",")
+			if len(bytes) != len(e.PCMem) {
+				return e, fmt.Errorf("invalid PCMEM in trace line %q", line)
+			}
+			for j, b := range bytes {
+				v, err := strconv.ParseUint(b, 16, 8)
+				if err != nil {
+					return e, fmt.Errorf("invalid PCMEM in trace line %q", line)
+				}
+				e.PCMem[j] = byte(v)
+			}
+		}
+	}
+	return e, nil
+}
+
+func (e TraceEntry) appendBinary(b []byte) []byte {
+	b = append(b, e.A, e.F, e.B, e.C, e.D, e.E, e.H, e.L)
+	b = binary.LittleEndian.AppendUint16(b, e.SP)
+	b = binary.LittleEndian.AppendUint16(b, e.PC)
+	return append(b, e.PCMem[:]...)
+}
+
+func decodeTraceRecord(b []byte) TraceEntry {
+	e := TraceEntry{A: b[0], F: b[1], B: b[2], C: b[3], D: b[4], E: b[5], H: b[6], L: b[7]}
+	e.SP = binary.LittleEndian.Uint16(b[8:])
+	e.PC = binary.LittleEndian.Uint16(b[10:])
+	copy(e.PCMem[:], b[12:16])
+	return e
+}
+
+// TraceReader streams the entries of a trace.
+type TraceReader struct {
+	r      *bufio.Reader
+	format TraceFormat
+	line   int
+}
+
+func NewTraceReader(r io.Reader, format TraceFormat) *TraceReader {
+	return &TraceReader{r: bufio.NewReaderSize(r, 64 << 10), format: format}
+}
+
+// DetectTraceFormat picks the format from the start of the trace. Doctor
+// logs start with "A:", which can't start a binary record since the low
+// nibble of F is always clear.
+func DetectTraceFormat(r *bufio.Reader) TraceFormat {
+	if start, _ := r.Peek(2); string(start) == "A:" {
+		return TraceDoctor
+	}
+	return TraceBinary
+}
+
+// Next returns the next entry, or io.EOF at the end of the trace.
+func (t *TraceReader) Next() (TraceEntry, error) {
+	if t.format == TraceBinary {
+		var record [traceRecordSize]byte
+		if _, err := io.ReadFull(t.r, record[:]); err != nil {
+			if err == io.ErrUnexpectedEOF {
+				err = fmt.Errorf("truncated trace record %d", t.line + 1)
+			}
+			return TraceEntry{}, err
+		}
+		t.line++
+		return decodeTraceRecord(record[:]), nil
+	}
+	for {
+		line, err := t.r.ReadString('\n')
+		if line == "" && err != nil {
+			return TraceEntry{}, err
+		}
+		t.line++
+		if line = strings.TrimSpace(line); line == "" {
+			continue
+		}
+		e, err := ParseTraceEntry(line)
+		if err != nil {
+			return e, fmt.Errorf("line %d: %w", t.line, err)
+		}
+		return e, nil
+	}
+}
+
+// TraceEntry returns the current register state as logged in traces.
+func (cpu *CPU) TraceEntry() TraceEntry {
+	e := TraceEntry{
+		A: cpu.AF.Hi(), F: cpu.AF.Lo(),
+		B: cpu.BC.Hi(), C: cpu.BC.Lo(),
+		D: cpu.DE.Hi(), E: cpu.DE.Lo(),
+		H: cpu.HL.Hi(), L: cpu.HL.Lo(),
+		SP: cpu.SP.value, PC: cpu.PC,
+	}
+	for i := range e.PCMem {
+		e.PCMem[i] = cpu.Bus.Peek(cpu.PC + uint16(i))
+	}
+	return e
+}
+
+type traceWriter struct {
+	w      *bufio.Writer
+	format TraceFormat
+	buf    []byte
+}
+
+// StartTrace writes an entry to w before every instruction, until
+// StopTrace.
+func (gb *GB) StartTrace(w io.Writer, format TraceFormat) {
+	gb.CPU.trace = &traceWriter{w: bufio.NewWriterSize(w, 64 << 10), format: format}
+	gb.PPU.StubLY = true
+}
+
+// StopTrace flushes the trace and stops tracing.
+func (gb *GB) StopTrace() error {
+	if gb.CPU.trace == nil {
+		return nil
+	}
+	err := gb.CPU.trace.w.Flush()
+	gb.CPU.trace = nil
+	gb.PPU.StubLY = false
+	return err
+}
+
+func (gb *GB) Tracing() bool {
+	return gb.CPU.trace != nil
+}
+
+func (cpu *CPU) writeTrace() {
+	t := cpu.trace
+	e := cpu.TraceEntry()
+	if t.format == TraceBinary {
+		t.buf = e.appendBinary(t.buf[:0])
+	} else {
+		t.buf = append(append(t.buf[:0], e.String()...), '\n')
+	}
+	t.w.Write(t.buf)
+}
@@ -0,0 +1,116 @@
+package gameboy
+
+/*
+Memory watchpoints.
+
+Watchpoints are checked on every CPU access going through MMU.ReadAt and
+MMU.WriteAt, IO registers included. Devices updating IF aren't CPU accesses
+and don't trigger them. A hit doesn't interrupt the instruction
+doing the access: it is recorded and Step returns it once the instruction
+completes, so the debugger stops right after the offending instruction.
+*/
+import "fmt"
+
+type WatchKind uint8
+
+const (
+	WatchRead WatchKind = 1 << iota
+	WatchWrite
+	// Writes which change the stored value.
+	WatchChange
+)
+
+func (k WatchKind) String() string {
+	switch k {
+	case WatchRead:
+		return "read"
+	case WatchWrite:
+		return "write"
+	case WatchChange:
+		return "change"
+	case WatchRead | WatchWrite:
+		return "access"
+	}
+	return fmt.Sprintf("WatchKind(%d)", uint8(k))
+}
+
+type Watchpoint struct {
+	ID int
+	// Watched range, both ends inclusive.
+	Start, End uint16
+	Kind WatchKind
+}
+
+// WatchpointHit is returned by Step after an instruction triggered a
+// watchpoint. It wraps ErrBreak.
+type WatchpointHit struct {
+	Watchpoint *Watchpoint
+	// Kind of access which triggered the watchpoint.
+	Kind WatchKind
+	Addr uint16
+	// Value before and after a write, Old is the value read for reads.
+	Old, New byte
+	// Address of the instruction doing the access.
+	PC uint16
+}
+
+func (h *WatchpointHit) Error() string {
+	if h.Kind == WatchRead {
+		return fmt.Sprintf("watchpoint %d: read %#02x from %#04x at pc %#04x", h.Watchpoint.ID, h.Old, h.Addr, h.PC)
+	}
+	return fmt.Sprintf("watchpoint %d: %s %#04x %#02x -> %#02x at pc %#04x", h.Watchpoint.ID, h.Kind, h.Addr, h.Old, h.New, h.PC)
+}
+
+func (h *WatchpointHit) Unwrap() error {
+	return ErrBreak
+}
+
+// AddWatchpoint watches accesses of the given kinds to start..end (inclusive).
+func (mmu *MMU) AddWatchpoint(start, end uint16, kind WatchKind) *Watchpoint {
+	mmu.nextWatchID++
+	wp := &Watchpoint{ID: mmu.nextWatchID, Start: start, End: end, Kind: kind}
+	mmu.watchpoints = append(mmu.watchpoints, wp)
+	return wp
+}
+
+func (mmu *MMU) RemoveWatchpoint(id int) bool {
+	for i, wp := range mmu.watchpoints {
+		if wp.ID == id {
+			mmu.watchpoints = append(mmu.watchpoints[:i], mmu.watchpoints[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (mmu *MMU) Watchpoints() []*Watchpoint {
+	return mmu.watchpoints
+}
+
+// Records the first watchpoint triggered by the current instruction.
+func (mmu *MMU) checkWatchpoints(addr uint16, kind WatchKind, oldVal, newVal byte) {
+	if mmu.watchHit != nil {
+		return
+	}
+	for _, wp := range mmu.watchpoints {
+		if addr < wp.Start || addr > wp.End {
+			continue
+		}
+		hitKind := wp.Kind & kind
+		if wp.Kind & WatchChange != 0 && kind == WatchWrite && oldVal != newVal {
+			hitKind = WatchChange
+		}
+		if hitKind == 0 {
+			continue
+		}
+		mmu.watchHit = &WatchpointHit{Watchpoint: wp, Kind: hitKind, Addr: addr, Old: oldVal, New: newVal}
+		return
+	}
+}
+
+// Returns and clears the watchpoint hit by the last instruction, if any.
+func (mmu *MMU) takeWatchHit() *WatchpointHit {
+	hit := mmu.watchHit
+	mmu.watchHit = nil
+	return hit
+}
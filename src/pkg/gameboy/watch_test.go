@@ -0,0 +1,29 @@
+package gameboy
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWatchpointIgnoresInterruptRequests(t *testing.T) {
+	// LD A,$04 / LDH [$FF0F],A / NOP
+	gb := newTestGB(t, testROM(0x3E, 0x04, 0xE0, 0x0F, 0x00))
+	gb.MMU.AddWatchpoint(IF_ADDR, IF_ADDR, WatchRead | WatchWrite)
+
+	gb.RequestInterrupt(INT_SERIAL)
+	if _, err := gb.Step(); err != nil {
+		t.Fatalf("interrupt request hit a watchpoint: %v", err)
+	}
+	if gb.MMU.Peek(IF_ADDR) & (1 << INT_SERIAL) == 0 {
+		t.Fatalf("IF = %#02x, serial interrupt not requested", gb.MMU.Peek(IF_ADDR))
+	}
+
+	_, err := gb.Step()
+	var hit *WatchpointHit
+	if !errors.As(err, &hit) {
+		t.Fatalf("CPU write to IF returned %v, want a watchpoint hit", err)
+	}
+	if hit.Addr != IF_ADDR || hit.Kind != WatchWrite {
+		t.Errorf("hit %v, want a write to %#04x", hit, IF_ADDR)
+	}
+}
@@ -0,0 +1,491 @@
+// GDB remote serial protocol stub.
+//
+// The SM83 has no upstream GDB target, so register numbers follow this
+// layout, each register 16 bits little endian:
+//
+//	0: AF  1: BC  2: DE  3: HL  4: SP  5: PC
+//
+// The layout is described to the client in target.xml, served through
+// qXfer:features:read.
+//
+// Supported packets: ? g G p P m M c s Z0-Z4 z0-z4 k D H qSupported
+// qXfer:features:read qAttached qC qfThreadInfo qsThreadInfo QStartNoAckMode.
+// Anything else gets the empty "unsupported" reply.
+package gdbstub
+
+import (
+	"bufio"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"gopherboy/pkg/gameboy"
+	"io"
+	"log"
+	"net"
+	"strconv"
+	"strings"
+)
+
+const numRegisters = 6
+
+// Largest packet accepted and sent, advertised in qSupported.
+const packetSize = 0x4000
+
+const targetXML = `<?xml version="1.0"?>
+<!DOCTYPE target SYSTEM "gdb-target.dtd">
+<target version="1.0">
+  <feature name="org.gopherboy.sm83">
+    <reg name="af" bitsize="16" type="uint16" regnum="0"/>
+    <reg name="bc" bitsize="16" type="uint16"/>
+    <reg name="de" bitsize="16" type="uint16"/>
+    <reg name="hl" bitsize="16" type="uint16"/>
+    <reg name="sp" bitsize="16" type="data_ptr"/>
+    <reg name="pc" bitsize="16" type="code_ptr"/>
+  </feature>
+</target>
+`
+
+// Instructions executed between two checks for a Ctrl-C from the client.
+const interruptPollInterval = 1024
+
+type event struct {
+	packet string
+	// Ctrl-C (0x03) received out of band.
+	interrupt bool
+	// Packet with a bad checksum, has to be NAKed.
+	corrupt bool
+}
+
+type watchKey struct {
+	kind  byte
+	addr  uint16
+	size  uint16
+}
+
+type Stub struct {
+	gb *gameboy.GB
+
+	breakpoints map[uint16]bool
+	// Watchpoint IDs by the Z packet that created them.
+	watchpoints map[watchKey]int
+}
+
+func New(gb *gameboy.GB) *Stub {
+	return &Stub{
+		gb: gb,
+		breakpoints: map[uint16]bool{},
+		watchpoints: map[watchKey]int{},
+	}
+}
+
+// ListenAndServe accepts debugger connections on addr, one at a time.
+func (s *Stub) ListenAndServe(addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	defer l.Close()
+	log.Printf("gdbstub: listening on %s", l.Addr())
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+		log.Printf("gdbstub: client connected from %s", conn.RemoteAddr())
+		err = s.Serve(conn)
+		conn.Close()
+		if err != nil && !errors.Is(err, io.EOF) {
+			log.Printf("gdbstub: %v", err)
+		}
+	}
+}
+
+type session struct {
+	*Stub
+	conn   io.ReadWriter
+	events chan event
+	noAck  bool
+}
+
+// Serve handles a single client until it detaches or disconnects. The
+// breakpoints and watchpoints it set are removed when it leaves.
+func (s *Stub) Serve(conn io.ReadWriter) error {
+	sess := &session{Stub: s, conn: conn, events: make(chan event, 16)}
+	defer s.clearBreakpoints()
+	errc := make(chan error, 1)
+	go func() {
+		errc <- readPackets(conn, sess.events)
+		close(sess.events)
+	}()
+	for ev := range sess.events {
+		if ev.interrupt {
+			// Not running, nothing to interrupt.
+			continue
+		}
+		if ev.corrupt {
+			io.WriteString(conn, "-")
+			continue
+		}
+		if !sess.noAck {
+			io.WriteString(conn, "+")
+		}
+		if ev.packet == "k" {
+			// Kill gets no reply, the client doesn't wait for one.
+			return nil
+		}
+		reply, done := sess.handle(ev.packet)
+		if err := sess.send(reply); err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+	}
+	return <-errc
+}
+
+// Splits the byte stream into packets, acknowledgements are ignored since
+// replies are never retransmitted.
+func readPackets(r io.Reader, events chan<- event) error {
+	br := bufio.NewReader(r)
+	for {
+		c, err := br.ReadByte()
+		if err != nil {
+			return err
+		}
+		switch c {
+		case 0x03:
+			events <- event{interrupt: true}
+		case '$':
+			data, err := br.ReadString('#')
+			if err != nil {
+				return err
+			}
+			var sum [2]byte
+			if _, err := io.ReadFull(br, sum[:]); err != nil {
+				return err
+			}
+			data = data[:len(data) - 1]
+			want, err := strconv.ParseUint(string(sum[:]), 16, 8)
+			if err != nil || byte(want) != checksum(data) {
+				events <- event{corrupt: true}
+				continue
+			}
+			events <- event{packet: data}
+		}
+	}
+}
+
+func checksum(data string) byte {
+	var sum byte
+	for i := 0; i < len(data); i++ {
+		sum += data[i]
+	}
+	return sum
+}
+
+func (sess *session) send(reply string) error {
+	_, err := fmt.Fprintf(sess.conn, "$%s#%02x", reply, checksum(reply))
+	return err
+}
+
+// Returns the reply to a packet and whether the session is over.
+func (sess *session) handle(packet string) (string, bool) {
+	if packet == "" {
+		return "", false
+	}
+	args := packet[1:]
+	switch packet[0] {
+	case '?':
+		return "S05", false
+	case 'g':
+		var out strings.Builder
+		for i := 0; i < numRegisters; i++ {
+			out.WriteString(encodeReg(sess.readReg(i)))
+		}
+		return out.String(), false
+	case 'G':
+		if len(args) != numRegisters * 4 {
+			return "E01", false
+		}
+		for i := 0; i < numRegisters; i++ {
+			v, err := decodeReg(args[i*4 : i*4+4])
+			if err != nil {
+				return "E01", false
+			}
+			sess.writeReg(i, v)
+		}
+		return "OK", false
+	case 'p':
+		n, err := strconv.ParseUint(args, 16, 8)
+		if err != nil || n >= numRegisters {
+			return "E01", false
+		}
+		return encodeReg(sess.readReg(int(n))), false
+	case 'P':
+		nStr, vStr, ok := strings.Cut(args, "=")
+		n, err := strconv.ParseUint(nStr, 16, 8)
+		if !ok || err != nil || n >= numRegisters {
+			return "E01", false
+		}
+		v, err := decodeReg(vStr)
+		if err != nil {
+			return "E01", false
+		}
+		sess.writeReg(int(n), v)
+		return "OK", false
+	case 'm':
+		addr, size, err := parseAddrLen(args)
+		if err != nil {
+			return "E01", false
+		}
+		// Each byte takes two characters, clients accept a shorter read.
+		buf := make([]byte, min(int(size), packetSize / 2))
+		for i := range buf {
+			buf[i] = sess.gb.MMU.Peek(addr + uint16(i))
+		}
+		return hex.EncodeToString(buf), false
+	case 'M':
+		head, data, ok := strings.Cut(args, ":")
+		addr, size, err := parseAddrLen(head)
+		if !ok || err != nil {
+			return "E01", false
+		}
+		buf, err := hex.DecodeString(data)
+		if err != nil || len(buf) != int(size) {
+			return "E01", false
+		}
+		for i, b := range buf {
+			sess.gb.MMU.Poke(addr + uint16(i), b)
+		}
+		return "OK", false
+	case 's':
+		return sess.resume(true), false
+	case 'c':
+		return sess.resume(false), false
+	case 'Z', 'z':
+		return sess.setBreakpoint(packet[0] == 'Z', args), false
+	case 'H':
+		return "OK", false
+	case 'D':
+		return "OK", true
+	case 'q':
+		switch {
+		case strings.HasPrefix(args, "Supported"):
+			return fmt.Sprintf("PacketSize=%x;QStartNoAckMode+;swbreak+;qXfer:features:read+", packetSize), false
+		case strings.HasPrefix(args, "Xfer:features:read:"):
+			return readFeatures(strings.TrimPrefix(args, "Xfer:features:read:")), false
+		case args == "Attached":
+			return "1", false
+		case args == "C":
+			return "QC1", false
+		case args == "fThreadInfo":
+			return "m1", false
+		case args == "sThreadInfo":
+			return "l", false
+		}
+	case 'Q':
+		if args == "StartNoAckMode" {
+			// The OK itself is still acknowledged by the client.
+			sess.noAck = true
+			return "OK", false
+		}
+	}
+	return "", false
+}
+
+// Executes a single instruction or runs until a breakpoint, watchpoint or
+// Ctrl-C, returning the stop reply.
+func (sess *session) resume(step bool) string {
+	gb := sess.gb
+	for n := 0; ; n++ {
+		if n > 0 && sess.breakpoints[gb.CPU.PC] {
+			return "T05swbreak:;"
+		}
+		if n % interruptPollInterval == 0 && sess.interrupted() {
+			return "S02"
+		}
+		_, err := gb.Step()
+		var hit *gameboy.WatchpointHit
+		switch {
+		case errors.As(err, &hit):
+			return fmt.Sprintf("T05%s:%04x;", watchStopReason(hit.Watchpoint.Kind), hit.Addr)
+		case err != nil:
+			log.Printf("gdbstub: %v", err)
+			// Unimplemented and illegal opcodes are the only way to fail.
+			return "S04"
+		}
+		if step {
+			return "S05"
+		}
+	}
+}
+
+// The stop reason matches the Z packet which set the watchpoint, not the
+// access which triggered it.
+func watchStopReason(kind gameboy.WatchKind) string {
+	switch kind {
+	case gameboy.WatchRead:
+		return "rwatch"
+	case gameboy.WatchRead | gameboy.WatchWrite:
+		return "awatch"
+	}
+	return "watch"
+}
+
+// Drains pending events, reporting whether the client asked to stop.
+func (sess *session) interrupted() bool {
+	for {
+		select {
+		case ev, ok := <-sess.events:
+			if !ok {
+				// Disconnected, stop so Serve can return.
+				return true
+			}
+			if ev.interrupt {
+				return true
+			}
+		default:
+			return false
+		}
+	}
+}
+
+// Serves "annex:offset,length" of qXfer:features:read, target.xml is the only
+// annex.
+func readFeatures(args string) string {
+	annex, rng, ok := strings.Cut(args, ":")
+	if !ok || annex != "target.xml" {
+		return "E00"
+	}
+	offStr, lenStr, ok := strings.Cut(rng, ",")
+	offset, err1 := strconv.ParseUint(offStr, 16, 32)
+	length, err2 := strconv.ParseUint(lenStr, 16, 32)
+	if !ok || err1 != nil || err2 != nil {
+		return "E00"
+	}
+	if offset >= uint64(len(targetXML)) {
+		return "l"
+	}
+	rest := targetXML[offset:]
+	length = min(length, packetSize - 1)
+	if uint64(len(rest)) <= length {
+		return "l" + rest
+	}
+	return "m" + rest[:length]
+}
+
+func (s *Stub) clearBreakpoints() {
+	clear(s.breakpoints)
+	for key, id := range s.watchpoints {
+		s.gb.MMU.RemoveWatchpoint(id)
+		delete(s.watchpoints, key)
+	}
+}
+
+// Handles Z/z packets: "type,addr,kind".
+func (sess *session) setBreakpoint(insert bool, args string) string {
+	parts := strings.Split(args, ",")
+	if len(parts) < 3 || len(parts[0]) != 1 {
+		return "E01"
+	}
+	addr, size, err := parseAddrLen(parts[1] + "," + parts[2])
+	if err != nil {
+		return "E01"
+	}
+	kind := parts[0][0]
+	switch kind {
+	case '0', '1':
+		if insert {
+			sess.breakpoints[addr] = true
+		} else {
+			delete(sess.breakpoints, addr)
+		}
+		return "OK"
+	case '2', '3', '4':
+		key := watchKey{kind: kind, addr: addr, size: size}
+		if !insert {
+			if id, ok := sess.watchpoints[key]; ok {
+				sess.gb.MMU.RemoveWatchpoint(id)
+				delete(sess.watchpoints, key)
+			}
+			return "OK"
+		}
+		if size == 0 {
+			return "E01"
+		}
+		watchKind := map[byte]gameboy.WatchKind{
+			'2': gameboy.WatchWrite,
+			'3': gameboy.WatchRead,
+			'4': gameboy.WatchRead | gameboy.WatchWrite,
+		}[kind]
+		wp := sess.gb.MMU.AddWatchpoint(addr, addr + size - 1, watchKind)
+		sess.watchpoints[key] = wp.ID
+		return "OK"
+	}
+	return ""
+}
+
+func (sess *session) readReg(n int) uint16 {
+	cpu := sess.gb.CPU
+	switch n {
+	case 0:
+		return cpu.AF.Value()
+	case 1:
+		return cpu.BC.Value()
+	case 2:
+		return cpu.DE.Value()
+	case 3:
+		return cpu.HL.Value()
+	case 4:
+		return cpu.SP.Value()
+	}
+	return cpu.PC
+}
+
+func (sess *session) writeReg(n int, v uint16) {
+	cpu := sess.gb.CPU
+	switch n {
+	case 0:
+		cpu.AF.Set(v)
+	case 1:
+		cpu.BC.Set(v)
+	case 2:
+		cpu.DE.Set(v)
+	case 3:
+		cpu.HL.Set(v)
+	case 4:
+		cpu.SP.Set(v)
+	case 5:
+		cpu.PC = v
+	}
+}
+
+// Registers are sent in target byte order (little endian).
+func encodeReg(v uint16) string {
+	return hex.EncodeToString([]byte{byte(v), byte(v >> 8)})
+}
+
+func decodeReg(s string) (uint16, error) {
+	b, err := hex.DecodeString(s)
+	if err != nil || len(b) != 2 {
+		return 0, fmt.Errorf("invalid register value %q", s)
+	}
+	return uint16(b[0]) | uint16(b[1]) << 8, nil
+}
+
+func parseAddrLen(s string) (uint16, uint16, error) {
+	addrStr, lenStr, ok := strings.Cut(s, ",")
+	if !ok {
+		return 0, 0, fmt.Errorf("missing length in %q", s)
+	}
+	addr, err := strconv.ParseUint(addrStr, 16, 16)
+	if err != nil {
+		return 0, 0, err
+	}
+	size, err := strconv.ParseUint(lenStr, 16, 16)
+	if err != nil || addr + size > 0x10000 {
+		return 0, 0, fmt.Errorf("invalid length in %q", s)
+	}
+	return uint16(addr), uint16(size), nil
+}
@@ -0,0 +1,250 @@
+package gdbstub
+
+import (
+	"bufio"
+	"fmt"
+	"gopherboy/pkg/gameboy"
+	"io"
+	"net"
+	"strings"
+	"testing"
+)
+
+// LD B,1 / LD A,[$C000] / LD [$C001],A / JR -2
+var testCode = []byte{0x06, 0x01, 0xFA, 0x00, 0xC0, 0xEA, 0x01, 0xC0, 0x18, 0xFE}
+
+func newTestGB(t *testing.T) *gameboy.GB {
+	t.Helper()
+	rom := make([]byte, 0x8000)
+	copy(rom[0x100:], testCode)
+	gb, err := gameboy.NewGBFromROM(rom)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return gb
+}
+
+type client struct {
+	t    *testing.T
+	conn net.Conn
+	r    *bufio.Reader
+	// Result of Serve.
+	done chan error
+}
+
+// Starts a session with s over an in-memory connection.
+func connect(t *testing.T, s *Stub) *client {
+	server, conn := net.Pipe()
+	c := &client{t: t, conn: conn, r: bufio.NewReader(conn), done: make(chan error, 1)}
+	go func() {
+		err := s.Serve(server)
+		server.Close()
+		c.done <- err
+	}()
+	t.Cleanup(func() { conn.Close() })
+	return c
+}
+
+func (c *client) write(raw string) {
+	c.t.Helper()
+	if _, err := io.WriteString(c.conn, raw); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+func (c *client) expect(want byte) {
+	c.t.Helper()
+	b, err := c.r.ReadByte()
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	if b != want {
+		c.t.Fatalf("got %q, want %q", b, want)
+	}
+}
+
+// Reads a reply packet, checking its checksum.
+func (c *client) reply() string {
+	c.t.Helper()
+	c.expect('$')
+	data, err := c.r.ReadString('#')
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	data = data[:len(data) - 1]
+	var sum [2]byte
+	if _, err := io.ReadFull(c.r, sum[:]); err != nil {
+		c.t.Fatal(err)
+	}
+	if want := fmt.Sprintf("%02x", checksum(data)); string(sum[:]) != want {
+		c.t.Fatalf("reply %q has checksum %s, want %s", data, sum[:], want)
+	}
+	return data
+}
+
+// Sends a packet and returns the reply.
+func (c *client) exchange(packet string) string {
+	c.t.Helper()
+	c.write(fmt.Sprintf("$%s#%02x", packet, checksum(packet)))
+	c.expect('+')
+	return c.reply()
+}
+
+func (c *client) detach() {
+	c.t.Helper()
+	if got := c.exchange("D"); got != "OK" {
+		c.t.Fatalf("D = %q, want OK", got)
+	}
+	if err := <-c.done; err != nil {
+		c.t.Fatalf("Serve: %v", err)
+	}
+}
+
+func TestChecksum(t *testing.T) {
+	c := connect(t, New(newTestGB(t)))
+	c.write("$g#00")
+	c.expect('-')
+	// The stub keeps going after a corrupt packet.
+	if got := c.exchange("?"); got != "S05" {
+		t.Errorf("? = %q, want S05", got)
+	}
+	c.detach()
+}
+
+func TestRegisters(t *testing.T) {
+	gb := newTestGB(t)
+	gb.CPU.AF.Set(0x12B0)
+	gb.CPU.BC.Set(0x3456)
+	gb.CPU.DE.Set(0x789A)
+	gb.CPU.HL.Set(0xBCDE)
+	gb.CPU.SP.Set(0xFFFE)
+	c := connect(t, New(gb))
+	if got, want := c.exchange("g"), "b0125634"+"9a78"+"debc"+"feff"+"0001"; got != want {
+		t.Errorf("g = %q, want %q", got, want)
+	}
+	if got := c.exchange("P1=2211"); got != "OK" {
+		t.Errorf("P1 = %q, want OK", got)
+	}
+	if got := c.exchange("p1"); got != "2211" {
+		t.Errorf("p1 = %q, want 2211", got)
+	}
+	c.detach()
+}
+
+func TestReadMemory(t *testing.T) {
+	gb := newTestGB(t)
+	c := connect(t, New(gb))
+	if got := c.exchange("m100,5"); got != "0601fa00c0" {
+		t.Errorf("m100,5 = %q, want 0601fa00c0", got)
+	}
+	if got := c.exchange("Mc000,2:abcd"); got != "OK" {
+		t.Errorf("M = %q, want OK", got)
+	}
+	if got := c.exchange("mc000,2"); got != "abcd" {
+		t.Errorf("mc000,2 = %q, want abcd", got)
+	}
+	// Reads are cut short to fit in a packet.
+	if got := c.exchange("m0,ffff"); len(got) != packetSize {
+		t.Errorf("m0,ffff returned %d characters, want %d", len(got), packetSize)
+	}
+	c.detach()
+}
+
+func TestBreakpoint(t *testing.T) {
+	gb := newTestGB(t)
+	c := connect(t, New(gb))
+	if got := c.exchange("Z0,102,1"); got != "OK" {
+		t.Fatalf("Z0 = %q, want OK", got)
+	}
+	if got := c.exchange("c"); got != "T05swbreak:;" {
+		t.Fatalf("c = %q, want T05swbreak:;", got)
+	}
+	if gb.CPU.PC != 0x0102 {
+		t.Errorf("stopped at %#04x, want 0x0102", gb.CPU.PC)
+	}
+	if got := c.exchange("z0,102,1"); got != "OK" {
+		t.Fatalf("z0 = %q, want OK", got)
+	}
+	if got := c.exchange("s"); got != "S05" {
+		t.Errorf("s = %q, want S05", got)
+	}
+	if gb.CPU.PC != 0x0105 {
+		t.Errorf("stepped to %#04x, want 0x0105", gb.CPU.PC)
+	}
+	c.detach()
+}
+
+func TestWatchpoints(t *testing.T) {
+	tests := []struct {
+		packet string
+		want   string
+	}{
+		{"Z2,c001,1", "T05watch:c001;"},
+		{"Z3,c000,1", "T05rwatch:c000;"},
+		// Reported as an access watchpoint although it was hit by a read.
+		{"Z4,c000,1", "T05awatch:c000;"},
+	}
+	for _, test := range tests {
+		c := connect(t, New(newTestGB(t)))
+		if got := c.exchange(test.packet); got != "OK" {
+			t.Fatalf("%s = %q, want OK", test.packet, got)
+		}
+		if got := c.exchange("c"); got != test.want {
+			t.Errorf("%s: c = %q, want %q", test.packet, got, test.want)
+		}
+		c.detach()
+	}
+}
+
+func TestSessionClearsBreakpoints(t *testing.T) {
+	gb := newTestGB(t)
+	s := New(gb)
+	c := connect(t, s)
+	for _, packet := range []string{"Z0,102,1", "Z2,c001,1", "Z4,c000,2"} {
+		if got := c.exchange(packet); got != "OK" {
+			t.Fatalf("%s = %q, want OK", packet, got)
+		}
+	}
+	c.detach()
+	if len(s.breakpoints) != 0 || len(s.watchpoints) != 0 || len(gb.MMU.Watchpoints()) != 0 {
+		t.Errorf("breakpoints %v, watchpoints %v left after detaching", s.breakpoints, gb.MMU.Watchpoints())
+	}
+}
+
+func TestKill(t *testing.T) {
+	c := connect(t, New(newTestGB(t)))
+	c.write(fmt.Sprintf("$k#%02x", checksum("k")))
+	c.expect('+')
+	if err := <-c.done; err != nil {
+		t.Fatalf("Serve: %v", err)
+	}
+	if b, err := c.r.ReadByte(); err != io.EOF {
+		t.Errorf("got %q after k, want no reply", b)
+	}
+}
+
+func TestTargetDescription(t *testing.T) {
+	c := connect(t, New(newTestGB(t)))
+	if got := c.exchange("qSupported:multiprocess+;xmlRegisters=i386"); !strings.Contains(got, "qXfer:features:read+") {
+		t.Errorf("qSupported = %q, doesn't advertise qXfer:features:read", got)
+	}
+	// Read in small chunks, the way a client with a small buffer would.
+	var xml strings.Builder
+	for {
+		got := c.exchange(fmt.Sprintf("qXfer:features:read:target.xml:%x,40", xml.Len()))
+		if got == "" || (got[0] != 'm' && got[0] != 'l') {
+			t.Fatalf("qXfer = %q", got)
+		}
+		xml.WriteString(got[1:])
+		if got[0] == 'l' {
+			break
+		}
+	}
+	if xml.String() != targetXML {
+		t.Errorf("target.xml = %q, want %q", xml.String(), targetXML)
+	}
+	if got := c.exchange("qXfer:features:read:other.xml:0,40"); got != "E00" {
+		t.Errorf("unknown annex = %q, want E00", got)
+	}
+	c.detach()
+}
@@ -0,0 +1,196 @@
+// Symbol tables produced by RGBDS (rgblink -n .sym and -m .map files).
+package symbols
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type Symbol struct {
+	Name string
+	Bank uint16
+	Addr uint16
+}
+
+func (s Symbol) String() string {
+	return fmt.Sprintf("%02x:%04x %s", s.Bank, s.Addr, s.Name)
+}
+
+type Table struct {
+	byName map[string]Symbol
+	// Sorted by bank, then address.
+	sorted []Symbol
+}
+
+func NewTable() *Table {
+	return &Table{byName: map[string]Symbol{}}
+}
+
+// Add inserts a symbol, replacing any symbol with the same name.
+func (t *Table) Add(sym Symbol) {
+	if old, ok := t.byName[sym.Name]; ok {
+		i := t.search(old.Bank, old.Addr)
+		for ; i < len(t.sorted); i++ {
+			if t.sorted[i].Name == old.Name {
+				t.sorted = append(t.sorted[:i], t.sorted[i+1:]...)
+				break
+			}
+		}
+	}
+	t.byName[sym.Name] = sym
+	i := t.search(sym.Bank, sym.Addr)
+	t.sorted = append(t.sorted, Symbol{})
+	copy(t.sorted[i+1:], t.sorted[i:])
+	t.sorted[i] = sym
+}
+
+// Index of the first symbol at or after bank:addr.
+func (t *Table) search(bank, addr uint16) int {
+	return sort.Search(len(t.sorted), func(i int) bool {
+		s := t.sorted[i]
+		return s.Bank > bank || (s.Bank == bank && s.Addr >= addr)
+	})
+}
+
+func (t *Table) Len() int {
+	if t == nil {
+		return 0
+	}
+	return len(t.sorted)
+}
+
+// Symbols returns all symbols ordered by bank and address.
+func (t *Table) Symbols() []Symbol {
+	if t == nil {
+		return nil
+	}
+	return t.sorted
+}
+
+func (t *Table) Lookup(name string) (Symbol, bool) {
+	if t == nil {
+		return Symbol{}, false
+	}
+	sym, ok := t.byName[name]
+	return sym, ok
+}
+
+// Name returns the first symbol defined exactly at bank:addr.
+func (t *Table) Name(bank, addr uint16) (string, bool) {
+	if t == nil {
+		return "", false
+	}
+	i := t.search(bank, addr)
+	if i < len(t.sorted) && t.sorted[i].Bank == bank && t.sorted[i].Addr == addr {
+		return t.sorted[i].Name, true
+	}
+	return "", false
+}
+
+// Nearest returns the closest symbol at or before bank:addr in the same
+// bank, along with the offset of addr from it.
+func (t *Table) Nearest(bank, addr uint16) (Symbol, uint16, bool) {
+	if t == nil {
+		return Symbol{}, 0, false
+	}
+	i := t.search(bank, addr)
+	if i < len(t.sorted) && t.sorted[i].Bank == bank && t.sorted[i].Addr == addr {
+		return t.sorted[i], 0, true
+	}
+	if i == 0 || t.sorted[i-1].Bank != bank {
+		return Symbol{}, 0, false
+	}
+	sym := t.sorted[i-1]
+	return sym, addr - sym.Addr, true
+}
+
+// Format renders an address as "Label" or "Label+offset", falling back to
+// the plain address when there is no symbol before it.
+func (t *Table) Format(bank, addr uint16) string {
+	sym, offset, ok := t.Nearest(bank, addr)
+	if !ok {
+		return fmt.Sprintf("$%04x", addr)
+	}
+	if offset == 0 {
+		return sym.Name
+	}
+	return fmt.Sprintf("%s+$%x", sym.Name, offset)
+}
+
+// Load reads a .sym or .map file, picking the parser by extension.
+func Load(path string) (*Table, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if strings.EqualFold(filepath.Ext(path), ".map") {
+		return ParseMap(f)
+	}
+	return ParseSym(f)
+}
+
+// ParseSym reads a .sym file made of "bank:addr Label" lines, with ";"
+// starting a comment.
+func ParseSym(r io.Reader) (*Table, error) {
+	t := NewTable()
+	scanner := bufio.NewScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line, _, _ := strings.Cut(scanner.Text(), ";")
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("line %d: expected \"bank:addr label\"", lineNo)
+		}
+		bankStr, addrStr, ok := strings.Cut(fields[0], ":")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected \"bank:addr label\"", lineNo)
+		}
+		bank, err := strconv.ParseUint(bankStr, 16, 16)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid bank %q", lineNo, bankStr)
+		}
+		addr, err := strconv.ParseUint(addrStr, 16, 16)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid address %q", lineNo, addrStr)
+		}
+		t.Add(Symbol{Name: fields[1], Bank: uint16(bank), Addr: uint16(addr)})
+	}
+	return t, scanner.Err()
+}
+
+// ParseMap reads the symbols listed in a .map file. Only the "<TYPE> bank #N:"
+// headers and "$addr = Label" lines are used, everything else is skipped.
+func ParseMap(r io.Reader) (*Table, error) {
+	t := NewTable()
+	bank := uint16(0)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if _, bankStr, ok := strings.Cut(line, " bank #"); ok && strings.HasSuffix(line, ":") {
+			n, err := strconv.ParseUint(strings.TrimSuffix(bankStr, ":"), 10, 16)
+			if err == nil {
+				bank = uint16(n)
+			}
+			continue
+		}
+		addrStr, name, ok := strings.Cut(line, " = ")
+		if !ok || !strings.HasPrefix(addrStr, "$") {
+			continue
+		}
+		addr, err := strconv.ParseUint(addrStr[1:], 16, 16)
+		if err != nil {
+			continue
+		}
+		t.Add(Symbol{Name: strings.TrimSpace(name), Bank: bank, Addr: uint16(addr)})
+	}
+	return t, scanner.Err()
+}
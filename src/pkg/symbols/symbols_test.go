@@ -0,0 +1,157 @@
+package symbols
+
+import (
+	"strings"
+	"testing"
+)
+
+const testSym = `; File generated by rgblink
+00:0100 Main
+00:0102 Main.loop   ; local label
+00:0150 Init
+01:4000 Bank1Func
+02:4000 Bank2Func
+02:4010 Bank2Func.done
+00:c000 wCounter
+`
+
+func TestParseSym(t *testing.T) {
+	table, err := ParseSym(strings.NewReader(testSym))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if table.Len() != 7 {
+		t.Errorf("got %d symbols, want 7", table.Len())
+	}
+	tests := []struct {
+		name string
+		bank uint16
+		addr uint16
+	}{
+		{"Main", 0, 0x0100},
+		{"Main.loop", 0, 0x0102},
+		{"Bank1Func", 1, 0x4000},
+		{"Bank2Func", 2, 0x4000},
+		{"Bank2Func.done", 2, 0x4010},
+		{"wCounter", 0, 0xC000},
+	}
+	for _, test := range tests {
+		sym, ok := table.Lookup(test.name)
+		if !ok || sym.Bank != test.bank || sym.Addr != test.addr {
+			t.Errorf("Lookup(%q) = %+v, %v, want %02x:%04x", test.name, sym, ok, test.bank, test.addr)
+		}
+		if name, ok := table.Name(test.bank, test.addr); !ok || name != test.name {
+			t.Errorf("Name(%02x:%04x) = %q, %v, want %q", test.bank, test.addr, name, ok, test.name)
+		}
+	}
+	// Ordered by bank, then address.
+	var names []string
+	for _, sym := range table.Symbols() {
+		names = append(names, sym.Name)
+	}
+	want := "Main Main.loop Init wCounter Bank1Func Bank2Func Bank2Func.done"
+	if got := strings.Join(names, " "); got != want {
+		t.Errorf("Symbols() = %s, want %s", got, want)
+	}
+}
+
+func TestParseSymMalformed(t *testing.T) {
+	for _, line := range []string{
+		"00:0100",
+		"0100 Main",
+		"00:0100 Main extra",
+		"zz:0100 Main",
+		"00:zzzz Main",
+		"00:10000 Main",
+	} {
+		_, err := ParseSym(strings.NewReader("00:0150 Init\n" + line + "\n"))
+		if err == nil {
+			t.Errorf("ParseSym(%q) succeeded, want an error", line)
+		} else if !strings.HasPrefix(err.Error(), "line 2:") {
+			t.Errorf("ParseSym(%q) = %v, want the error on line 2", line, err)
+		}
+	}
+}
+
+const testMap = `SUMMARY:
+	ROM0: 336 bytes used / 16048 free
+
+ROM0 bank #0:
+	SECTION: $0100-$014f ($0050 bytes) ["Header"]
+	         $0100 = Main
+	         $0102 = Main.loop
+	SECTION: $0150-$01ff ($00b0 bytes) ["Init"]
+	         $0150 = Init
+	         $zzzz = Broken
+	         $0160 = 
+	EMPTY: $0200-$3fff ($3e00 bytes)
+
+ROMX bank #2:
+	SECTION: $4000-$40ff ($0100 bytes) ["Bank2"]
+	         $4000 = Bank2Func
+	         $4010 = Bank2Func.done
+
+WRAM0 bank #0:
+	SECTION: $c000-$c001 ($0002 bytes) ["Variables"]
+	         $c000 = wCounter
+`
+
+func TestParseMap(t *testing.T) {
+	table, err := ParseMap(strings.NewReader(testMap))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		bank uint16
+		addr uint16
+	}{
+		{"Main", 0, 0x0100},
+		{"Main.loop", 0, 0x0102},
+		{"Init", 0, 0x0150},
+		{"Bank2Func", 2, 0x4000},
+		{"Bank2Func.done", 2, 0x4010},
+		{"wCounter", 0, 0xC000},
+	}
+	for _, test := range tests {
+		sym, ok := table.Lookup(test.name)
+		if !ok || sym.Bank != test.bank || sym.Addr != test.addr {
+			t.Errorf("Lookup(%q) = %+v, %v, want %02x:%04x", test.name, sym, ok, test.bank, test.addr)
+		}
+	}
+	// Malformed lines are skipped.
+	if _, ok := table.Lookup("Broken"); ok {
+		t.Errorf("symbol with an invalid address was added")
+	}
+	if table.Len() != len(tests) {
+		t.Errorf("got %d symbols, want %d", table.Len(), len(tests))
+	}
+}
+
+func TestFormat(t *testing.T) {
+	table, err := ParseSym(strings.NewReader(testSym))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		bank uint16
+		addr uint16
+		want string
+	}{
+		{0, 0x0100, "Main"},
+		{0, 0x0104, "Main.loop+$2"},
+		{0, 0x0050, "$0050"},
+		{2, 0x4020, "Bank2Func.done+$10"},
+		// Bank 3 has no symbols, the ones of bank 2 don't apply.
+		{3, 0x4000, "$4000"},
+	}
+	for _, test := range tests {
+		if got := table.Format(test.bank, test.addr); got != test.want {
+			t.Errorf("Format(%02x:%04x) = %q, want %q", test.bank, test.addr, got, test.want)
+		}
+	}
+	var nilTable *Table
+	if got := nilTable.Format(0, 0x0100); got != "$0100" {
+		t.Errorf("nil table Format = %q, want $0100", got)
+	}
+}